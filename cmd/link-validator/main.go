@@ -86,8 +86,30 @@ func main() {
 	if stats.NotFoundLinks > 0 {
 		slog.Error("Links not found", slog.Int("links", stats.NotFoundLinks))
 	}
+	if stats.UntrackedLinks > 0 {
+		slog.Error("Links to files not tracked by git", slog.Int("links", stats.UntrackedLinks))
+	}
+	if stats.NotPermalinks > 0 {
+		slog.Error("Links pinned to a branch instead of a commit", slog.Int("links", stats.NotPermalinks))
+	}
+	if stats.OutdatedLinks > 0 {
+		slog.Warn("Links to renamed, transferred or archived repositories", slog.Int("links", stats.OutdatedLinks))
+	}
+	if stats.StateViolations > 0 {
+		slog.Error("Links to issues and pull requests violating the state policies", slog.Int("links", stats.StateViolations))
+	}
+	if stats.StateWarnings > 0 {
+		slog.Warn("Links to issues and pull requests with the state policy warnings", slog.Int("links", stats.StateWarnings))
+	}
+	if stats.RateLimitedLinks > 0 {
+		slog.Warn("Links unverified because of GitHub rate limit", slog.Int("links", stats.RateLimitedLinks))
+	}
+
+	if stats.OrphanFiles > 0 {
+		slog.Error("Orphan documents found", slog.Int("files", stats.OrphanFiles))
+	}
 
-	if stats.Errors > 0 || stats.NotFoundLinks > 0 {
+	if stats.Errors > 0 || stats.NotFoundLinks > 0 || stats.UntrackedLinks > 0 || stats.NotPermalinks > 0 || stats.StateViolations > 0 || stats.OrphanFiles > 0 {
 		os.Exit(1)
 	}
 }
@@ -5,13 +5,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
+	"link-validator/pkg/actions"
 	"link-validator/pkg/config"
 	"link-validator/pkg/dd"
+	"link-validator/pkg/docgraph"
 	"link-validator/pkg/errs"
 	"link-validator/pkg/github"
 	"link-validator/pkg/http"
 	"link-validator/pkg/local-path"
+	"link-validator/pkg/wiki"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -28,20 +32,53 @@ type HttpValidatorExcluder interface {
 	Excludes(url string) bool
 }
 
+// Prefetcher is implemented by processors, which can validate links in batches.
+// All links of the processor are passed to Prefetch before the files are processed.
+type Prefetcher interface {
+	LinkProcessor
+	Prefetch(ctx context.Context, urls []string)
+}
+
+// FileFilter is implemented by processors, which extract links only from particular files, e.g. GitHub Actions workflows
+type FileFilter interface {
+	Accepts(fileName string) bool
+}
+
+// QuotaReporter is implemented by processors limited by an API quota, the quota summary is reported at the end of the run
+type QuotaReporter interface {
+	ReportQuota()
+}
+
 type Stats struct {
-	Lines         int
-	TotalLinks    int
-	Errors        int
-	NotFoundLinks int
-	Files         int
+	Lines            int
+	TotalLinks       int
+	Errors           int
+	NotFoundLinks    int
+	UntrackedLinks   int
+	RateLimitedLinks int
+	NotPermalinks    int
+	OutdatedLinks    int
+	StateViolations  int
+	StateWarnings    int
+	OrphanFiles      int
+	Files            int
 }
 
 type LinkValidator struct {
 	processors    []LinkProcessor
+	prefetchers   []Prefetcher
 	fileProcessor FileProcessorFunc
+	graph         *docgraph.Graph
+	graphCfg      config.DocsGraphConfig
+	// partialGraph is true if only the explicit files are processed, the graph misses the rest of the documents then
+	partialGraph bool
 }
 
 func New(cfg *config.Config) (*LinkValidator, error) {
+	var graph *docgraph.Graph
+	var localPathDelegate local_path.Delegate
+	var actionsDelegate actions.Delegate
+	var prefetchers []Prefetcher
 	processors := make([]LinkProcessor, 0)
 	httpExcluders := make([]HttpValidatorExcluder, 0)
 	if cfg.Validators.GitHub.IsEnabled() {
@@ -51,6 +88,11 @@ func New(cfg *config.Config) (*LinkValidator, error) {
 		}
 		httpExcluders = append(httpExcluders, ghValidator)
 		processors = append(processors, ghValidator)
+		localPathDelegate = ghValidator.Process
+		actionsDelegate = ghValidator.Process
+		if cfg.Validators.GitHub.IsGraphQL() {
+			prefetchers = append(prefetchers, ghValidator)
+		}
 	}
 	if cfg.Validators.DataDog.IsEnabled() {
 		ddValidator, err := dd.New(cfg)
@@ -61,7 +103,24 @@ func New(cfg *config.Config) (*LinkValidator, error) {
 		httpExcluders = append(httpExcluders, ddValidator)
 	}
 	if cfg.Validators.LocalPath.IsEnabled() {
-		processors = append(processors, local_path.New())
+		localPathValidator, err := local_path.New(cfg, localPathDelegate)
+		if err != nil {
+			return nil, err
+		}
+		processors = append(processors, localPathValidator)
+		graph = localPathValidator.Graph()
+	}
+
+	if cfg.Validators.Wiki.IsEnabled() {
+		wikiValidator, err := wiki.New(cfg)
+		if err != nil {
+			return nil, err
+		}
+		processors = append(processors, wikiValidator)
+	}
+
+	if cfg.Validators.Actions.IsEnabled() {
+		processors = append(processors, actions.New(cfg, actionsDelegate))
 	}
 
 	if cfg.Validators.HTTP.IsEnabled() {
@@ -82,13 +141,22 @@ func New(cfg *config.Config) (*LinkValidator, error) {
 		slog.Warn("you have no validators set up, what are you trying to validate? :)")
 	}
 
+	validator := &LinkValidator{
+		processors:   processors,
+		prefetchers:  prefetchers,
+		graph:        graph,
+		graphCfg:     cfg.Validators.LocalPath.DocsGraph,
+		partialGraph: cfg.Files != nil,
+	}
 	if len(cfg.Files) != 0 {
-		return &LinkValidator{processors, includeFilesPipeline(cfg)}, nil
+		validator.fileProcessor = includeFilesPipeline(cfg)
 	} else if cfg.Files != nil {
 		slog.Warn("env var FILES is empty, hence there is nothing to validate")
-		return &LinkValidator{processors, emptyPipeline()}, nil
+		validator.fileProcessor = emptyPipeline()
+	} else {
+		validator.fileProcessor = walkFilesPipeline(cfg)
 	}
-	return &LinkValidator{processors, walkFilesPipeline(cfg)}, nil
+	return validator, nil
 }
 
 func emptyPipeline() FileProcessorFunc {
@@ -117,10 +185,14 @@ func walkFilesPipeline(cfg *config.Config) FileProcessorFunc {
 
 func (v *LinkValidator) ProcessFiles(ctx context.Context, filesList []string) Stats {
 	stats := Stats{}
+	v.prefetch(ctx, filesList)
 
 	for _, fileName := range filesList {
 		slog.Debug("Processing file", slog.String("fileName", fileName))
 		stats.Files++
+		if v.graph != nil {
+			v.graph.AddNode(fileName)
+		}
 		f, err := os.Open(fileName)
 		if err != nil {
 			slog.With("error", err).Error("Error opening file", slog.String("file", fileName))
@@ -141,7 +213,7 @@ func (v *LinkValidator) ProcessFiles(ctx context.Context, filesList []string) St
 				lines++
 				continue
 			}
-			links := v.processLine(line, lines)
+			links := v.processLine(fileName, line, lines)
 			for link, processor := range links {
 				err = processor.Process(ctx, link, fileName)
 				linksFound++
@@ -156,6 +228,26 @@ func (v *LinkValidator) ProcessFiles(ctx context.Context, filesList []string) St
 				} else if errors.Is(err, errs.ErrEmptyBody) {
 					slog.Warn("not found", slog.String("link", link), slog.String("error", err.Error()), slog.String("filename", fileName), slog.Int("line", lines+1))
 					stats.NotFoundLinks++
+				} else if errors.Is(err, errs.ErrNotTracked) {
+					slog.Warn("not tracked by git", slog.String("link", link), slog.String("error", err.Error()), slog.String("filename", fileName), slog.Int("line", lines+1))
+					stats.UntrackedLinks++
+				} else if errors.Is(err, errs.ErrNotPermalink) {
+					slog.Warn("not a permalink", slog.String("link", link), slog.String("error", err.Error()), slog.String("filename", fileName), slog.Int("line", lines+1))
+					stats.NotPermalinks++
+				} else if errors.Is(err, errs.ErrRepoMoved) || errors.Is(err, errs.ErrRepoArchived) {
+					slog.Warn("outdated repository", slog.String("link", link), slog.String("error", err.Error()), slog.String("filename", fileName), slog.Int("line", lines+1))
+					stats.OutdatedLinks++
+				} else if stateErr := (errs.StatePolicyError{}); errors.As(err, &stateErr) {
+					if stateErr.Fail {
+						slog.Error("state policy violated", slog.String("link", link), slog.String("error", err.Error()), slog.String("filename", fileName), slog.Int("line", lines+1))
+						stats.StateViolations++
+					} else {
+						slog.Warn("state policy violated", slog.String("link", link), slog.String("error", err.Error()), slog.String("filename", fileName), slog.Int("line", lines+1))
+						stats.StateWarnings++
+					}
+				} else if errors.Is(err, errs.ErrRateLimited) {
+					slog.Warn("unverified (rate limited)", slog.String("link", link), slog.String("filename", fileName), slog.Int("line", lines+1))
+					stats.RateLimitedLinks++
 				} else {
 					stats.Errors++
 					slog.With("error", err).Error("error validating link", slog.String("link", link), slog.String("filename", fileName), slog.Int("line", lines+1))
@@ -175,9 +267,82 @@ func (v *LinkValidator) ProcessFiles(ctx context.Context, filesList []string) St
 
 		slog.Info("Processed", slog.Int("lines", lines), slog.Int("links", linksFound), slog.String("fileName", fileName))
 	}
+	v.analyseDocsGraph(&stats)
+	for _, p := range v.processors {
+		if reporter, ok := p.(QuotaReporter); ok {
+			reporter.ReportQuota()
+		}
+		if closer, ok := p.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				slog.With("error", err).Error("can't close the processor")
+			}
+		}
+	}
 	return stats
 }
 
+// prefetch collects the links of the processors supporting batch validation and passes them to the processors
+func (v *LinkValidator) prefetch(ctx context.Context, filesList []string) {
+	if len(v.prefetchers) == 0 {
+		return
+	}
+	links := make([][]string, len(v.prefetchers))
+	for _, fileName := range filesList {
+		f, err := os.Open(fileName)
+		if err != nil {
+			continue // reported during processing
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 1024*1024), 1024*1024) // 1 MB
+		codeSnippet := false
+		for scanner.Scan() {
+			line := scanner.Text()
+			if strings.HasPrefix(line, "```") {
+				codeSnippet = !codeSnippet
+			}
+			if codeSnippet {
+				continue
+			}
+			for i, p := range v.prefetchers {
+				links[i] = append(links[i], p.ExtractLinks(line)...)
+			}
+		}
+		_ = f.Close()
+	}
+	for i, p := range v.prefetchers {
+		p.Prefetch(ctx, links[i])
+	}
+}
+
+// analyseDocsGraph reports orphan documents and dead-ends found in the graph of local links
+// and exports the graph if it is configured. Orphans are not reported for the explicit files (FILES),
+// the documents linking to them are not in the graph.
+func (v *LinkValidator) analyseDocsGraph(stats *Stats) {
+	if v.graph == nil {
+		return
+	}
+	if v.partialGraph {
+		slog.Info("docs graph is built from the explicit files only, orphan documents are not reported")
+	} else {
+		for _, orphan := range v.graph.Orphans() {
+			slog.Warn("orphan document, not reachable from the entry points", slog.String("filename", orphan))
+			stats.OrphanFiles++
+		}
+	}
+	for _, deadEnd := range v.graph.DeadEnds() {
+		slog.Info("dead-end document, doesn't link to other documents", slog.String("filename", deadEnd))
+	}
+	if v.graphCfg.Format == "" {
+		return
+	}
+	if err := v.graph.ExportToFile(v.graphCfg.Output, v.graphCfg.Format); err != nil {
+		slog.With("error", err).Error("can't export docs graph", slog.String("file", v.graphCfg.Output))
+		stats.Errors++
+		return
+	}
+	slog.Info("Docs graph exported", slog.String("file", v.graphCfg.Output), slog.String("format", v.graphCfg.Format))
+}
+
 // matchesFileMask checks if a filename matches any of the provided file masks
 func matchesFileMask(filename string, masks []string) bool {
 	// Extract just the filename part from the full path
@@ -196,9 +361,12 @@ func (v *LinkValidator) GetFiles() ([]string, error) {
 	return v.fileProcessor([]string{})
 }
 
-func (v *LinkValidator) processLine(line string, lines int) map[string]LinkProcessor {
+func (v *LinkValidator) processLine(fileName, line string, lines int) map[string]LinkProcessor {
 	found := make(map[string]LinkProcessor)
 	for _, p := range v.processors {
+		if filter, ok := p.(FileFilter); ok && !filter.Accepts(fileName) {
+			continue
+		}
 		links := p.ExtractLinks(line)
 		for _, link := range links {
 			if foundProc, exist := found[link]; exist {
@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"link-validator/pkg/config"
+	"link-validator/pkg/docgraph"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -1237,3 +1238,29 @@ func TestWalkFilesPipeline(t *testing.T) {
 		})
 	}
 }
+
+func TestLinkValidator_analyseDocsGraph(t *testing.T) {
+	tests := []struct {
+		name         string
+		partialGraph bool
+		wantOrphans  int
+	}{
+		{name: "all documents are processed", wantOrphans: 1},
+		{name: "explicit files are processed", partialGraph: true, wantOrphans: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			graph := docgraph.New(root, []string{"README.md"})
+			graph.AddNode(filepath.Join(root, "README.md"))
+			graph.AddNode(filepath.Join(root, "docs", "setup.md"))
+			v := &LinkValidator{graph: graph, partialGraph: tt.partialGraph}
+
+			stats := Stats{}
+			v.analyseDocsGraph(&stats)
+			if stats.OrphanFiles != tt.wantOrphans {
+				t.Errorf("OrphanFiles = %d, want %d", stats.OrphanFiles, tt.wantOrphans)
+			}
+		})
+	}
+}
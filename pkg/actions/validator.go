@@ -0,0 +1,143 @@
+// Package actions implements validation of GitHub Actions 'uses:' references
+// in workflows (.github/workflows/*.yml) and action.yml files of composite actions.
+// Remote actions are validated by the GitHub processor: the repository, the ref (tag, branch or SHA)
+// and the action.yml/action.yaml file in the action path must exist.
+// Example: uses: actions/checkout@v4, uses: github/codeql-action/init@v3
+// Local actions and reusable workflows are resolved against the repository root on disk.
+// Example: uses: ./.github/actions/setup, uses: ./.github/workflows/build.yml
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"link-validator/pkg/config"
+	"link-validator/pkg/errs"
+	"link-validator/pkg/local-path"
+	"link-validator/pkg/regex"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// actionFiles are the metadata files of an action, GitHub looks them up in this order
+var actionFiles = []string{"action.yml", "action.yaml"}
+
+// Delegate validates the GitHub url by the GitHub processor
+type Delegate func(ctx context.Context, url string, testFileName string) error
+
+type LinkProcessor struct {
+	root     string
+	delegate Delegate
+	// results caches the validation results of the remote actions, which are usually referenced by many workflows
+	results map[string]error
+}
+
+func New(cfg *config.Config, delegate Delegate) *LinkProcessor {
+	return &LinkProcessor{
+		// GitHub resolves local actions against the repository root, the lookup path might be its subdirectory
+		root:     local_path.FindRepoRoot(cfg.LookupPath),
+		delegate: delegate,
+		results:  make(map[string]error),
+	}
+}
+
+// Accepts returns true for workflow files and action metadata files, 'uses:' entries are meaningless elsewhere
+func (proc *LinkProcessor) Accepts(fileName string) bool {
+	slashed := filepath.ToSlash(fileName)
+	base := path.Base(slashed)
+	if base == "action.yml" || base == "action.yaml" {
+		return true
+	}
+	return isWorkflow(slashed) && strings.Contains("/"+path.Dir(slashed)+"/", "/.github/workflows/")
+}
+
+func (proc *LinkProcessor) ExtractLinks(line string) []string {
+	m := regex.ActionUses.FindStringSubmatch(line)
+	if m == nil {
+		return nil
+	}
+	uses := m[1]
+	if strings.HasPrefix(uses, "docker://") || strings.Contains(uses, "${{") {
+		// docker images aren't GitHub references, expressions can't be resolved statically
+		return nil
+	}
+	return []string{uses}
+}
+
+func (proc *LinkProcessor) Process(ctx context.Context, uses string, testFileName string) error {
+	slog.Debug("actions: starting validation", slog.String("uses", uses))
+
+	if strings.HasPrefix(uses, "./") {
+		return proc.processLocal(uses)
+	}
+	if proc.delegate == nil {
+		slog.Debug("actions: GitHub processor is disabled, skipping", slog.String("uses", uses))
+		return nil
+	}
+	if err, ok := proc.results[uses]; ok {
+		return err
+	}
+	err := proc.processRemote(ctx, uses, testFileName)
+	proc.results[uses] = err
+	return err
+}
+
+// processLocal checks that the local action directory contains the action metadata file,
+// or the local reusable workflow exists
+func (proc *LinkProcessor) processLocal(uses string) error {
+	target := filepath.Join(proc.root, filepath.FromSlash(uses))
+	if isWorkflow(uses) {
+		if _, err := os.Stat(target); err != nil {
+			return errs.NewNotFoundMessage(fmt.Sprintf("reusable workflow '%s' not found", uses))
+		}
+		return nil
+	}
+	for _, name := range actionFiles {
+		if _, err := os.Stat(filepath.Join(target, name)); err == nil {
+			return nil
+		}
+	}
+	return errs.NewNotFoundMessage(fmt.Sprintf("local action '%s' not found, there is no action.yml or action.yaml in '%s'", uses, target))
+}
+
+// processRemote validates 'owner/repo[/path]@ref' via the GitHub processor
+func (proc *LinkProcessor) processRemote(ctx context.Context, uses string, testFileName string) error {
+	action, ref, ok := strings.Cut(uses, "@")
+	parts := strings.SplitN(action, "/", 3)
+	if !ok || ref == "" || len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("invalid action reference '%s', expected 'owner/repo[/path]@ref'", uses)
+	}
+	repoURL := fmt.Sprintf("https://github.com/%s/%s", parts[0], parts[1])
+	actionPath := ""
+	if len(parts) == 3 {
+		actionPath = strings.Trim(parts[2], "/")
+	}
+	if isWorkflow(actionPath) {
+		return proc.delegate(ctx, fmt.Sprintf("%s/blob/%s/%s", repoURL, ref, actionPath), testFileName)
+	}
+
+	for _, name := range actionFiles {
+		err := proc.delegate(ctx, fmt.Sprintf("%s/blob/%s/%s", repoURL, ref, path.Join(actionPath, name)), testFileName)
+		if !errors.Is(err, errs.ErrNotFound) {
+			return err
+		}
+	}
+	// tells apart the missing repository, ref or path from the missing metadata file
+	treeURL := fmt.Sprintf("%s/tree/%s", repoURL, ref)
+	if actionPath != "" {
+		treeURL += "/" + actionPath
+	}
+	if err := proc.delegate(ctx, treeURL, testFileName); err != nil {
+		return err
+	}
+	return errs.NewNotFoundMessage(fmt.Sprintf("action '%s' has no action.yml or action.yaml", uses))
+}
+
+func isWorkflow(p string) bool {
+	ext := path.Ext(p)
+	return ext == ".yml" || ext == ".yaml"
+}
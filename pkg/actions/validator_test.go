@@ -0,0 +1,251 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"link-validator/pkg/config"
+	"link-validator/pkg/errs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLinkProcessor_ExtractLinks(t *testing.T) {
+	t.Parallel()
+
+	proc := &LinkProcessor{}
+
+	tests := []struct {
+		name string
+		line string
+		want []string
+	}{
+		{
+			name: "step",
+			line: `      - uses: actions/checkout@v4`,
+			want: []string{"actions/checkout@v4"},
+		},
+		{
+			name: "step with name",
+			line: `        uses: github/codeql-action/init@0123456789abcdef0123456789abcdef01234567 # v3.1.0`,
+			want: []string{"github/codeql-action/init@0123456789abcdef0123456789abcdef01234567"},
+		},
+		{
+			name: "quoted reusable workflow",
+			line: `    uses: "your-ko/workflows/.github/workflows/build.yml@main"`,
+			want: []string{"your-ko/workflows/.github/workflows/build.yml@main"},
+		},
+		{
+			name: "local action",
+			line: `      - uses: './.github/actions/setup'`,
+			want: []string{"./.github/actions/setup"},
+		},
+		{
+			name: "docker image",
+			line: `      - uses: docker://alpine:3.8`,
+			want: nil,
+		},
+		{
+			name: "expression",
+			line: `      - uses: ${{ matrix.action }}`,
+			want: nil,
+		},
+		{
+			name: "commented out step",
+			line: `      # - uses: actions/checkout@v4`,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := proc.ExtractLinks(tt.line)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ExtractLinks mismatch\nline=%q\ngot = %#v\nwant= %#v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkProcessor_Accepts(t *testing.T) {
+	proc := &LinkProcessor{}
+	tests := []struct {
+		fileName string
+		want     bool
+	}{
+		{fileName: ".github/workflows/ci.yml", want: true},
+		{fileName: "repo/.github/workflows/release.yaml", want: true},
+		{fileName: ".github/actions/setup/action.yml", want: true},
+		{fileName: "action.yaml", want: true},
+		{fileName: ".github/dependabot.yml", want: false},
+		{fileName: "docs/workflows/ci.yml", want: false},
+		{fileName: "README.md", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fileName, func(t *testing.T) {
+			if got := proc.Accepts(tt.fileName); got != tt.want {
+				t.Errorf("Accepts(%q) = %v, want %v", tt.fileName, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkProcessor_Process(t *testing.T) {
+	tmp := t.TempDir()
+	for _, f := range []string{
+		".github/actions/setup/action.yml",
+		".github/actions/lint/action.yaml",
+		".github/actions/empty/README.md",
+		".github/workflows/build.yml",
+	} {
+		full := filepath.Join(tmp, f)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(full, []byte("name: test"), 0o644); err != nil {
+			t.Fatalf("write file: %v", err)
+		}
+	}
+
+	// existing is the fake GitHub repository 'your-ko/actions' at the ref 'v1'
+	existing := map[string]bool{
+		"https://github.com/your-ko/actions/blob/v1/action.yml":                     true,
+		"https://github.com/your-ko/actions/blob/v1/lint/action.yaml":               true,
+		"https://github.com/your-ko/actions/blob/v1/.github/workflows/build.yml":    true,
+		"https://github.com/your-ko/actions/tree/v1/docs":                           true,
+		"https://github.com/your-ko/actions/blob/v1/.github/workflows/missing.yaml": false,
+	}
+
+	tests := []struct {
+		name      string
+		uses      string
+		wantErr   string
+		wantIs    error
+		wantCalls []string
+	}{
+		{name: "local action", uses: "./.github/actions/setup"},
+		{name: "local action with action.yaml", uses: "./.github/actions/lint"},
+		{name: "local reusable workflow", uses: "./.github/workflows/build.yml"},
+		{
+			name:    "local action without metadata",
+			uses:    "./.github/actions/empty",
+			wantIs:  errs.ErrNotFound,
+			wantErr: "local action './.github/actions/empty' not found, there is no action.yml or action.yaml in '" + filepath.Join(tmp, ".github/actions/empty") + "'",
+		},
+		{
+			name:    "missing local reusable workflow",
+			uses:    "./.github/workflows/deploy.yml",
+			wantIs:  errs.ErrNotFound,
+			wantErr: "reusable workflow './.github/workflows/deploy.yml' not found",
+		},
+		{
+			name:      "remote action",
+			uses:      "your-ko/actions@v1",
+			wantCalls: []string{"https://github.com/your-ko/actions/blob/v1/action.yml"},
+		},
+		{
+			name: "remote action in a subdirectory with action.yaml",
+			uses: "your-ko/actions/lint@v1",
+			wantCalls: []string{
+				"https://github.com/your-ko/actions/blob/v1/lint/action.yml",
+				"https://github.com/your-ko/actions/blob/v1/lint/action.yaml",
+			},
+		},
+		{
+			name:      "remote reusable workflow",
+			uses:      "your-ko/actions/.github/workflows/build.yml@v1",
+			wantCalls: []string{"https://github.com/your-ko/actions/blob/v1/.github/workflows/build.yml"},
+		},
+		{
+			name:   "missing tag",
+			uses:   "your-ko/actions@v2",
+			wantIs: errs.ErrNotFound,
+			wantCalls: []string{
+				"https://github.com/your-ko/actions/blob/v2/action.yml",
+				"https://github.com/your-ko/actions/blob/v2/action.yaml",
+				"https://github.com/your-ko/actions/tree/v2",
+			},
+		},
+		{
+			name:    "directory without metadata",
+			uses:    "your-ko/actions/docs@v1",
+			wantIs:  errs.ErrNotFound,
+			wantErr: "action 'your-ko/actions/docs@v1' has no action.yml or action.yaml",
+			wantCalls: []string{
+				"https://github.com/your-ko/actions/blob/v1/docs/action.yml",
+				"https://github.com/your-ko/actions/blob/v1/docs/action.yaml",
+				"https://github.com/your-ko/actions/tree/v1/docs",
+			},
+		},
+		{
+			name:    "reference without ref",
+			uses:    "your-ko/actions",
+			wantErr: "invalid action reference 'your-ko/actions', expected 'owner/repo[/path]@ref'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			delegate := func(_ context.Context, url string, _ string) error {
+				calls = append(calls, url)
+				if !existing[url] {
+					return errs.NewNotFound(url)
+				}
+				return nil
+			}
+			proc := New(&config.Config{LookupPath: tmp}, delegate)
+
+			// the second call is answered from the cache
+			for range 2 {
+				err := proc.Process(context.Background(), tt.uses, ".github/workflows/ci.yml")
+				if tt.wantErr == "" && tt.wantIs == nil {
+					if err != nil {
+						t.Fatalf("Process() error = %v", err)
+					}
+				} else {
+					if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+						t.Errorf("expected \n errors.Is(err, %v) to be true; \n got err=%v", tt.wantIs, err)
+					}
+					if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+						t.Errorf("Process() error = %v, want %s", err, tt.wantErr)
+					}
+				}
+			}
+			if len(calls) != 0 || len(tt.wantCalls) != 0 {
+				if !reflect.DeepEqual(calls, tt.wantCalls) {
+					t.Errorf("delegated urls mismatch\ngot = %#v\nwant= %#v", calls, tt.wantCalls)
+				}
+			}
+		})
+	}
+}
+
+func TestLinkProcessor_Process_lookupPathInSubdirectory(t *testing.T) {
+	tmp := t.TempDir()
+	for _, dir := range []string{".git", ".github/actions/setup", "docs"} {
+		if err := os.MkdirAll(filepath.Join(tmp, dir), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmp, ".github/actions/setup/action.yml"), []byte("name: test"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	// local actions are resolved against the git toplevel, not the lookup path
+	proc := New(&config.Config{LookupPath: filepath.Join(tmp, "docs")}, nil)
+	if err := proc.Process(context.Background(), "./.github/actions/setup", ".github/workflows/ci.yml"); err != nil {
+		t.Errorf("Process() error = %v", err)
+	}
+}
+
+func TestLinkProcessor_Process_withoutGitHub(t *testing.T) {
+	proc := New(&config.Config{LookupPath: t.TempDir()}, nil)
+	if err := proc.Process(context.Background(), "your-ko/actions@v1", ".github/workflows/ci.yml"); err != nil {
+		t.Errorf("Process() error = %v, remote actions should be skipped without the GitHub processor", err)
+	}
+}
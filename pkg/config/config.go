@@ -66,6 +66,33 @@ func readFromEnv() (*Config, error) {
 	if corpPAT := GetEnv("CORP_PAT", ""); corpPAT != "" {
 		cfg.Validators.GitHub.CorpPAT = corpPAT
 	}
+	if appIDStr := GetEnv("GITHUB_APP_ID", ""); appIDStr != "" {
+		appID, err := strconv.ParseInt(appIDStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GitHub App id: %s", appIDStr)
+		}
+		cfg.Validators.GitHub.App.AppID = appID
+	}
+	if installationIDStr := GetEnv("GITHUB_APP_INSTALLATION_ID", ""); installationIDStr != "" {
+		installationID, err := strconv.ParseInt(installationIDStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GitHub App installation id: %s", installationIDStr)
+		}
+		cfg.Validators.GitHub.App.InstallationID = installationID
+	}
+	if privateKey := GetEnv("GITHUB_APP_PRIVATE_KEY", ""); privateKey != "" {
+		cfg.Validators.GitHub.App.PrivateKey = privateKey
+	}
+	if privateKeyFile := GetEnv("GITHUB_APP_PRIVATE_KEY_FILE", ""); privateKeyFile != "" {
+		cfg.Validators.GitHub.App.PrivateKeyFile = privateKeyFile
+	}
+	if rateLimitWaitStr := GetEnv("RATE_LIMIT_WAIT", ""); rateLimitWaitStr != "" {
+		rateLimitWait, err := time.ParseDuration(rateLimitWaitStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration value: %s", rateLimitWaitStr)
+		}
+		cfg.Validators.GitHub.RateLimitWait = &rateLimitWait
+	}
 	if ddApiKey := GetEnv("DD_API_KEY", ""); ddApiKey != "" {
 		cfg.Validators.DataDog.ApiKey = ddApiKey
 	}
@@ -136,9 +163,58 @@ func (cfg *Config) merge(merge *Config) {
 	if merge.Validators.GitHub.CorpPAT != "" {
 		cfg.Validators.GitHub.CorpPAT = merge.Validators.GitHub.CorpPAT
 	}
+	if merge.Validators.GitHub.App.AppID != 0 {
+		cfg.Validators.GitHub.App.AppID = merge.Validators.GitHub.App.AppID
+	}
+	if merge.Validators.GitHub.App.InstallationID != 0 {
+		cfg.Validators.GitHub.App.InstallationID = merge.Validators.GitHub.App.InstallationID
+	}
+	if merge.Validators.GitHub.App.PrivateKey != "" {
+		cfg.Validators.GitHub.App.PrivateKey = merge.Validators.GitHub.App.PrivateKey
+	}
+	if merge.Validators.GitHub.App.PrivateKeyEnv != "" {
+		cfg.Validators.GitHub.App.PrivateKeyEnv = merge.Validators.GitHub.App.PrivateKeyEnv
+	}
+	if merge.Validators.GitHub.App.PrivateKeyFile != "" {
+		cfg.Validators.GitHub.App.PrivateKeyFile = merge.Validators.GitHub.App.PrivateKeyFile
+	}
+	if merge.Validators.GitHub.Permalinks.Enabled != nil {
+		cfg.Validators.GitHub.Permalinks.Enabled = merge.Validators.GitHub.Permalinks.Enabled
+	}
+	if merge.Validators.GitHub.Permalinks.LineAnchorsOnly != nil {
+		cfg.Validators.GitHub.Permalinks.LineAnchorsOnly = merge.Validators.GitHub.Permalinks.LineAnchorsOnly
+	}
+	if merge.Validators.GitHub.Permalinks.Fix != nil {
+		cfg.Validators.GitHub.Permalinks.Fix = merge.Validators.GitHub.Permalinks.Fix
+	}
+	if merge.Validators.GitHub.StatePolicies.ClosedIssue != "" {
+		cfg.Validators.GitHub.StatePolicies.ClosedIssue = merge.Validators.GitHub.StatePolicies.ClosedIssue
+	}
+	if merge.Validators.GitHub.StatePolicies.LockedIssue != "" {
+		cfg.Validators.GitHub.StatePolicies.LockedIssue = merge.Validators.GitHub.StatePolicies.LockedIssue
+	}
+	if merge.Validators.GitHub.StatePolicies.LabeledIssue != "" {
+		cfg.Validators.GitHub.StatePolicies.LabeledIssue = merge.Validators.GitHub.StatePolicies.LabeledIssue
+	}
+	cfg.Validators.GitHub.StatePolicies.IssueLabels = mergeSlices(cfg.Validators.GitHub.StatePolicies.IssueLabels, merge.Validators.GitHub.StatePolicies.IssueLabels)
+	if merge.Validators.GitHub.StatePolicies.UnmergedPull != "" {
+		cfg.Validators.GitHub.StatePolicies.UnmergedPull = merge.Validators.GitHub.StatePolicies.UnmergedPull
+	}
+	if len(merge.Validators.GitHub.Hosts) > 0 {
+		cfg.Validators.GitHub.Hosts = merge.Validators.GitHub.Hosts
+	}
 	if merge.Validators.GitHub.PAT != "" {
 		cfg.Validators.GitHub.PAT = merge.Validators.GitHub.PAT
 	}
+	if merge.Validators.GitHub.CacheFile != "" {
+		cfg.Validators.GitHub.CacheFile = merge.Validators.GitHub.CacheFile
+	}
+	if merge.Validators.GitHub.GraphQL != nil {
+		cfg.Validators.GitHub.GraphQL = merge.Validators.GitHub.GraphQL
+	}
+	if merge.Validators.GitHub.RateLimitWait != nil {
+		cfg.Validators.GitHub.RateLimitWait = merge.Validators.GitHub.RateLimitWait
+	}
 
 	if merge.Validators.DataDog.Enabled != nil {
 		cfg.Validators.DataDog.Enabled = merge.Validators.DataDog.Enabled
@@ -153,6 +229,33 @@ func (cfg *Config) merge(merge *Config) {
 	if merge.Validators.LocalPath.Enabled != nil {
 		cfg.Validators.LocalPath.Enabled = merge.Validators.LocalPath.Enabled
 	}
+	if merge.Validators.LocalPath.TrackedOnly != nil {
+		cfg.Validators.LocalPath.TrackedOnly = merge.Validators.LocalPath.TrackedOnly
+	}
+	if merge.Validators.LocalPath.Submodules != "" {
+		cfg.Validators.LocalPath.Submodules = merge.Validators.LocalPath.Submodules
+	}
+	cfg.Validators.LocalPath.AllowOutside = mergeSlices(cfg.Validators.LocalPath.AllowOutside, merge.Validators.LocalPath.AllowOutside)
+	if merge.Validators.LocalPath.DocsGraph.Enabled != nil {
+		cfg.Validators.LocalPath.DocsGraph.Enabled = merge.Validators.LocalPath.DocsGraph.Enabled
+	}
+	cfg.Validators.LocalPath.DocsGraph.EntryPoints = mergeSlices(cfg.Validators.LocalPath.DocsGraph.EntryPoints, merge.Validators.LocalPath.DocsGraph.EntryPoints)
+	if merge.Validators.LocalPath.DocsGraph.Format != "" {
+		cfg.Validators.LocalPath.DocsGraph.Format = merge.Validators.LocalPath.DocsGraph.Format
+	}
+	if merge.Validators.LocalPath.DocsGraph.Output != "" {
+		cfg.Validators.LocalPath.DocsGraph.Output = merge.Validators.LocalPath.DocsGraph.Output
+	}
+
+	if merge.Validators.Wiki.Enabled != nil {
+		cfg.Validators.Wiki.Enabled = merge.Validators.Wiki.Enabled
+	}
+	if merge.Validators.Wiki.Root != "" {
+		cfg.Validators.Wiki.Root = merge.Validators.Wiki.Root
+	}
+	if merge.Validators.Actions.Enabled != nil {
+		cfg.Validators.Actions.Enabled = merge.Validators.Actions.Enabled
+	}
 
 	if merge.Validators.HTTP.Enabled != nil {
 		cfg.Validators.HTTP.Enabled = merge.Validators.HTTP.Enabled
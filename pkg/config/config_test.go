@@ -76,6 +76,34 @@ func TestConfig_merge(t *testing.T) {
 				Timeout:   5 * time.Second,
 			},
 		},
+		{
+			name: "merge overwrites the default rateLimitWait with an explicit zero",
+			fields: fields{
+				cfg: &Config{
+					Validators: ValidatorsConfig{
+						GitHub: GitHubConfig{
+							RateLimitWait: durationPtr(time.Minute),
+						},
+					},
+				},
+			},
+			args: args{
+				config: &Config{
+					Validators: ValidatorsConfig{
+						GitHub: GitHubConfig{
+							RateLimitWait: durationPtr(0),
+						},
+					},
+				},
+			},
+			want: &Config{
+				Validators: ValidatorsConfig{
+					GitHub: GitHubConfig{
+						RateLimitWait: durationPtr(0),
+					},
+				},
+			},
+		},
 		{
 			name: "merge overwrites non empty values",
 			fields: fields{
@@ -99,6 +127,7 @@ func TestConfig_merge(t *testing.T) {
 							Enabled:       boolPtr(true),
 							PAT:           "NEW_PAT",
 							CorpGitHubUrl: "NEW_URL",
+							Hosts:         []GitHubHost{{Url: "https://code.acquired.io", TokenEnv: "ACQUIRED_PAT"}},
 						},
 						HTTP: HttpConfig{
 							Enabled: boolPtr(true),
@@ -115,6 +144,7 @@ func TestConfig_merge(t *testing.T) {
 						PAT:           "NEW_PAT",
 						CorpPAT:       "OLD_PAT",
 						CorpGitHubUrl: "NEW_URL",
+						Hosts:         []GitHubHost{{Url: "https://code.acquired.io", TokenEnv: "ACQUIRED_PAT"}},
 					},
 					HTTP: HttpConfig{
 						Enabled: boolPtr(true),
@@ -264,6 +294,35 @@ func TestConfig_merge(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "merge local path allowed paths",
+			fields: fields{
+				cfg: &Config{
+					Validators: ValidatorsConfig{
+						LocalPath: LocalPathConfig{
+							Enabled: boolPtr(true),
+						},
+					},
+				},
+			},
+			args: args{
+				config: &Config{
+					Validators: ValidatorsConfig{
+						LocalPath: LocalPathConfig{
+							AllowOutside: []string{"../other-repo"},
+						},
+					},
+				},
+			},
+			want: &Config{
+				Validators: ValidatorsConfig{
+					LocalPath: LocalPathConfig{
+						Enabled:      boolPtr(true),
+						AllowOutside: []string{"../other-repo"},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -314,6 +373,10 @@ timeout: 5s
 validators:
   github:
     corpUrl: "https://github.mycorp.com"
+    hosts:
+      - url: "https://code.acquired.io"
+        tokenEnv: ACQUIRED_PAT
+        apiPrefix: /github/api/v3
   http:
     ignore:
      - "example.com"
@@ -325,6 +388,9 @@ validators:
 				Validators: ValidatorsConfig{
 					GitHub: GitHubConfig{
 						CorpGitHubUrl: "https://github.mycorp.com",
+						Hosts: []GitHubHost{
+							{Url: "https://code.acquired.io", TokenEnv: "ACQUIRED_PAT", ApiPrefix: "/github/api/v3"},
+						},
 					},
 					HTTP: HttpConfig{
 						Ignore: []string{"example.com", "test.org"},
@@ -333,6 +399,21 @@ validators:
 			},
 			wantErr: false,
 		},
+		{
+			name: "explicit zero rateLimitWait is kept",
+			fields: fields{
+				config: `validators:
+  github:
+    rateLimitWait: 0s`,
+			},
+			want: &Config{
+				Validators: ValidatorsConfig{
+					GitHub: GitHubConfig{
+						RateLimitWait: durationPtr(0),
+					},
+				},
+			},
+		},
 		{
 			name: "partial config loads only specified fields",
 			fields: fields{
@@ -2,7 +2,10 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
+	"net/url"
+	"strings"
 	"time"
 )
 
@@ -20,11 +23,49 @@ type Config struct {
 	Validators ValidatorsConfig `yaml:"validators"`
 }
 
-type ValidatorConfig struct {
+type LocalPathConfig struct {
 	Enabled *bool `yaml:"enabled"`
+	// AllowOutside lists paths outside the repository root (relative to the root), which local links may point to.
+	// Useful for monorepo setups that intentionally link to sibling checkouts.
+	AllowOutside []string `yaml:"allowOutside"`
+	// TrackedOnly enables checking link targets against the git index, so links to untracked/ignored files are reported
+	TrackedOnly *bool `yaml:"trackedOnly"`
+	// Submodules defines what to do with links into submodules which are not checked out:
+	// 'skip' them (default) or delegate them to the 'github' processor at the pinned submodule commit
+	Submodules string          `yaml:"submodules"`
+	DocsGraph  DocsGraphConfig `yaml:"docsGraph"`
 }
 
-func (cfg ValidatorConfig) validate() error {
+func (cfg LocalPathConfig) validate() error {
+	switch cfg.Submodules {
+	case "", "skip", "github":
+	default:
+		return fmt.Errorf("unsupported submodules mode '%s', expected 'skip' or 'github'", cfg.Submodules)
+	}
+	return cfg.DocsGraph.validate()
+}
+
+// DocsGraphConfig configures the analysis of the graph built from the local links between documents
+type DocsGraphConfig struct {
+	Enabled     *bool    `yaml:"enabled"`
+	EntryPoints []string `yaml:"entryPoints"`
+	// Format of the graph export, either 'dot' or 'mermaid'. The graph is not exported if empty.
+	Format string `yaml:"format"`
+	Output string `yaml:"output"`
+}
+
+// DefaultEntryPoints are used when no docs graph entry points are configured
+var DefaultEntryPoints = []string{"README.md", "docs/index.md"}
+
+func (cfg DocsGraphConfig) validate() error {
+	switch cfg.Format {
+	case "", "dot", "mermaid":
+	default:
+		return fmt.Errorf("unsupported docs graph format '%s', expected 'dot' or 'mermaid'", cfg.Format)
+	}
+	if cfg.Format != "" && cfg.Output == "" {
+		return errors.New("docs graph format is set, but the output file is not")
+	}
 	return nil
 }
 
@@ -33,18 +74,177 @@ type GitHubConfig struct {
 	PAT           string
 	CorpPAT       string
 	CorpGitHubUrl string `yaml:"corpUrl"`
+	// Hosts lists additional GitHub Enterprise Server instances, links are routed to the instance by host
+	Hosts []GitHubHost `yaml:"hosts"`
+	// App authenticates github.com requests as a GitHub App installation instead of PAT
+	App GitHubAppConfig `yaml:"app"`
+	// RateLimitWait is the total time the GitHub processor may pause waiting for the rate limit reset.
+	// When the budget is spent, the remaining GitHub links are reported as unverified. An explicit 0 disables waiting.
+	RateLimitWait *time.Duration `yaml:"rateLimitWait"`
+	// CacheFile persists GitHub API responses between runs, so they are requested conditionally (ETag/Last-Modified)
+	// and unchanged responses don't count against the rate limit
+	CacheFile string `yaml:"cacheFile"`
+	// GraphQL enables resolving repositories, issues, PRs, releases and discussions in batched GraphQL queries
+	GraphQL *bool `yaml:"graphql"`
+	// Permalinks configures the policy for code links pinned to a branch
+	Permalinks PermalinksConfig `yaml:"permalinks"`
+	// StatePolicies configures the policies for the state of linked issues and pull requests
+	StatePolicies StatePoliciesConfig `yaml:"statePolicies"`
+}
+
+// PermalinksConfig configures the policy, which reports blob/tree/blame links pinned to a branch
+// instead of a commit SHA or a tag. Such links drift as the branch moves.
+type PermalinksConfig struct {
+	Enabled *bool `yaml:"enabled"`
+	// LineAnchorsOnly limits the policy to the links with line anchors, e.g. '#L42'
+	LineAnchorsOnly *bool `yaml:"lineAnchorsOnly"`
+	// Fix resolves the branch to its current commit SHA and proposes the permalink in the report
+	Fix *bool `yaml:"fix"`
 }
 
 func (cfg GitHubConfig) validate() error {
+	if cfg.RateLimitBudget() < 0 {
+		return errors.New("rateLimitWait should not be negative")
+	}
+	if err := cfg.App.validate(); err != nil {
+		return err
+	}
+	if err := cfg.StatePolicies.validate(); err != nil {
+		return err
+	}
 	if cfg.CorpGitHubUrl != "" {
 		if cfg.CorpPAT == "" {
 			return errors.New("it seems you set CORP_URL but didn't provide CORP_PAT. Expect false negatives because the " +
 				"link-validator won't be able to fetch corl github without token")
 		}
 	}
+	seen := make(map[string]bool)
+	for _, host := range cfg.Hosts {
+		if err := host.validate(); err != nil {
+			return err
+		}
+		hostname, _ := host.Hostname()
+		if seen[hostname] {
+			return fmt.Errorf("GitHub host '%s' is configured more than once", hostname)
+		}
+		seen[hostname] = true
+	}
 	return nil
 }
 
+// StatePoliciesConfig configures the policies for the state of linked issues and pull requests.
+// Each policy is either 'warn' or 'fail', empty policies are disabled.
+type StatePoliciesConfig struct {
+	ClosedIssue string `yaml:"closedIssue"`
+	LockedIssue string `yaml:"lockedIssue"`
+	// LabeledIssue applies to the issues with any of IssueLabels
+	LabeledIssue string   `yaml:"labeledIssue"`
+	IssueLabels  []string `yaml:"issueLabels"`
+	// UnmergedPull applies to the pull requests closed without merging
+	UnmergedPull string `yaml:"unmergedPull"`
+}
+
+func (cfg StatePoliciesConfig) validate() error {
+	policies := []struct{ name, value string }{
+		{"closedIssue", cfg.ClosedIssue},
+		{"lockedIssue", cfg.LockedIssue},
+		{"labeledIssue", cfg.LabeledIssue},
+		{"unmergedPull", cfg.UnmergedPull},
+	}
+	for _, policy := range policies {
+		switch policy.value {
+		case "", "warn", "fail":
+		default:
+			return fmt.Errorf("unsupported %s policy '%s', expected 'warn' or 'fail'", policy.name, policy.value)
+		}
+	}
+	if (cfg.LabeledIssue == "") != (len(cfg.IssueLabels) == 0) {
+		return errors.New("labeledIssue policy and issueLabels should be set together")
+	}
+	return nil
+}
+
+// HasIssuePolicies returns true if any of the issue policies is enabled
+func (cfg StatePoliciesConfig) HasIssuePolicies() bool {
+	return cfg.ClosedIssue != "" || cfg.LockedIssue != "" || cfg.LabeledIssue != ""
+}
+
+// GitHubHost describes a GitHub Enterprise Server instance
+type GitHubHost struct {
+	// Url is the web url of the instance, e.g. 'https://github.mycorp.com'
+	Url string `yaml:"url"`
+	// TokenEnv is the name of the environment variable with the token for the instance
+	TokenEnv string `yaml:"tokenEnv"`
+	// ApiPrefix is the path of the REST API, '/api/v3' if empty
+	ApiPrefix string `yaml:"apiPrefix"`
+	// App authenticates the requests as a GitHub App installation instead of the token
+	App GitHubAppConfig `yaml:"app"`
+}
+
+func (cfg GitHubHost) validate() error {
+	if _, err := cfg.Hostname(); err != nil {
+		return err
+	}
+	if cfg.TokenEnv == "" && !cfg.App.IsSet() {
+		return fmt.Errorf("GitHub host '%s' has neither tokenEnv nor app. Expect false negatives because the "+
+			"link-validator won't be able to fetch it without token", cfg.Url)
+	}
+	if err := cfg.App.validate(); err != nil {
+		return fmt.Errorf("GitHub host '%s': %w", cfg.Url, err)
+	}
+	if cfg.ApiPrefix != "" && !strings.HasPrefix(cfg.ApiPrefix, "/") {
+		return fmt.Errorf("apiPrefix of GitHub host '%s' should start with '/'", cfg.Url)
+	}
+	return nil
+}
+
+// Hostname returns the lower-cased host of the instance url, e.g. 'github.mycorp.com'
+func (cfg GitHubHost) Hostname() (string, error) {
+	u, err := url.Parse(cfg.Url)
+	if err != nil || u.Scheme == "" || u.Hostname() == "" {
+		return "", fmt.Errorf("invalid GitHub host url: '%s'", cfg.Url)
+	}
+	return strings.ToLower(u.Hostname()), nil
+}
+
+// GitHubAppConfig configures the authentication as a GitHub App installation.
+// Installation tokens are minted from the private key and refreshed before they expire.
+type GitHubAppConfig struct {
+	AppID          int64 `yaml:"appId"`
+	InstallationID int64 `yaml:"installationId"`
+	// PrivateKey is the PEM encoded private key of the app, set via GITHUB_APP_PRIVATE_KEY
+	PrivateKey string `yaml:"-"`
+	// PrivateKeyEnv is the name of the environment variable with the PEM encoded private key
+	PrivateKeyEnv string `yaml:"privateKeyEnv"`
+	// PrivateKeyFile is the path to the PEM file with the private key
+	PrivateKeyFile string `yaml:"privateKeyFile"`
+}
+
+func (cfg GitHubAppConfig) validate() error {
+	if !cfg.IsSet() {
+		if cfg.InstallationID != 0 || cfg.PrivateKey != "" || cfg.PrivateKeyEnv != "" || cfg.PrivateKeyFile != "" {
+			return errors.New("GitHub App is partially configured, appId is not set")
+		}
+		return nil
+	}
+	if cfg.InstallationID <= 0 {
+		return errors.New("GitHub App installationId should be a positive integer")
+	}
+	sources := 0
+	for _, source := range []string{cfg.PrivateKey, cfg.PrivateKeyEnv, cfg.PrivateKeyFile} {
+		if source != "" {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return errors.New("GitHub App requires exactly one private key source: GITHUB_APP_PRIVATE_KEY, privateKeyEnv or privateKeyFile")
+	}
+	return nil
+}
+
+// IsSet returns true if the authentication as GitHub App is configured
+func (cfg GitHubAppConfig) IsSet() bool { return cfg.AppID != 0 }
+
 type DataDogConfig struct {
 	Enabled *bool `yaml:"enabled"`
 	ApiKey  string
@@ -58,6 +258,24 @@ func (cfg DataDogConfig) validate() error {
 	return nil
 }
 
+type WikiConfig struct {
+	Enabled *bool  `yaml:"enabled"`
+	Root    string `yaml:"root"`
+}
+
+func (cfg WikiConfig) validate() error {
+	return nil
+}
+
+// ActionsConfig configures the validation of GitHub Actions 'uses:' references in workflows and action.yml files
+type ActionsConfig struct {
+	Enabled *bool `yaml:"enabled"`
+}
+
+func (cfg ActionsConfig) validate() error {
+	return nil
+}
+
 type HttpConfig struct {
 	Enabled   *bool    `yaml:"enabled"`
 	Redirects int      `yaml:"redirects"`
@@ -73,17 +291,37 @@ func (cfg HttpConfig) validate() error {
 
 func boolPtr(b bool) *bool { return &b }
 
+func durationPtr(d time.Duration) *time.Duration { return &d }
+
 func isEnabled(p *bool) bool { return p != nil && *p }
 
-func (cfg ValidatorConfig) IsEnabled() bool { return isEnabled(cfg.Enabled) }
-func (cfg GitHubConfig) IsEnabled() bool    { return isEnabled(cfg.Enabled) }
-func (cfg DataDogConfig) IsEnabled() bool   { return isEnabled(cfg.Enabled) }
-func (cfg HttpConfig) IsEnabled() bool      { return isEnabled(cfg.Enabled) }
+func (cfg LocalPathConfig) IsEnabled() bool          { return isEnabled(cfg.Enabled) }
+func (cfg LocalPathConfig) IsTrackedOnly() bool      { return isEnabled(cfg.TrackedOnly) }
+func (cfg DocsGraphConfig) IsEnabled() bool          { return isEnabled(cfg.Enabled) }
+func (cfg GitHubConfig) IsEnabled() bool             { return isEnabled(cfg.Enabled) }
+func (cfg GitHubConfig) IsGraphQL() bool             { return isEnabled(cfg.GraphQL) }
+func (cfg PermalinksConfig) IsEnabled() bool         { return isEnabled(cfg.Enabled) }
+func (cfg PermalinksConfig) IsLineAnchorsOnly() bool { return isEnabled(cfg.LineAnchorsOnly) }
+func (cfg PermalinksConfig) IsFix() bool             { return isEnabled(cfg.Fix) }
+func (cfg DataDogConfig) IsEnabled() bool            { return isEnabled(cfg.Enabled) }
+func (cfg HttpConfig) IsEnabled() bool               { return isEnabled(cfg.Enabled) }
+func (cfg WikiConfig) IsEnabled() bool               { return isEnabled(cfg.Enabled) }
+func (cfg ActionsConfig) IsEnabled() bool            { return isEnabled(cfg.Enabled) }
+
+// RateLimitBudget returns the total time the GitHub processor may wait for the rate limit reset, 0 if not set
+func (cfg GitHubConfig) RateLimitBudget() time.Duration {
+	if cfg.RateLimitWait == nil {
+		return 0
+	}
+	return *cfg.RateLimitWait
+}
 
 type ValidatorsConfig struct {
 	GitHub    GitHubConfig    `yaml:"github"`
 	DataDog   DataDogConfig   `yaml:"datadog"`
-	LocalPath ValidatorConfig `yaml:"localPath"`
+	LocalPath LocalPathConfig `yaml:"localPath"`
+	Wiki      WikiConfig      `yaml:"wiki"`
+	Actions   ActionsConfig   `yaml:"actions"`
 	HTTP      HttpConfig      `yaml:"http"`
 }
 
@@ -92,6 +330,8 @@ func (v ValidatorsConfig) validate() []error {
 		v.GitHub,
 		v.DataDog,
 		v.LocalPath,
+		v.Wiki,
+		v.Actions,
 		v.HTTP,
 	}
 
@@ -118,7 +358,8 @@ func Default() *Config {
 				Redirects: 3,
 			},
 			GitHub: GitHubConfig{
-				Enabled: boolPtr(true),
+				Enabled:       boolPtr(true),
+				RateLimitWait: durationPtr(time.Minute),
 			},
 		},
 	}
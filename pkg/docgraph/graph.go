@@ -0,0 +1,219 @@
+// Package docgraph implements the analysis of the documentation graph built from local links.
+// Nodes are the processed documents and edges are local links between them.
+// The graph is used to find orphans (documents not reachable from the entry points) and dead-ends
+// (documents without links to other documents), and can be exported as DOT or Mermaid for review.
+package docgraph
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	FormatDOT     = "dot"
+	FormatMermaid = "mermaid"
+)
+
+type Graph struct {
+	repoRoot    string
+	entryPoints []string
+	nodes       map[string]bool
+	edges       map[string]map[string]bool
+}
+
+func New(repoRoot string, entryPoints []string) *Graph {
+	entries := make([]string, 0, len(entryPoints))
+	for _, e := range entryPoints {
+		entries = append(entries, filepath.ToSlash(filepath.Clean(strings.TrimPrefix(e, "/"))))
+	}
+	return &Graph{
+		repoRoot:    repoRoot,
+		entryPoints: entries,
+		nodes:       make(map[string]bool),
+		edges:       make(map[string]map[string]bool),
+	}
+}
+
+// AddNode adds the document to the graph. The path is either absolute or relative to the working directory.
+func (g *Graph) AddNode(path string) {
+	rel, ok := g.relative(path)
+	if !ok {
+		return
+	}
+	g.nodes[rel] = true
+}
+
+// AddEdge adds the link between two files. Links pointing outside the repository are ignored.
+func (g *Graph) AddEdge(from, to string) {
+	relFrom, ok := g.relative(from)
+	if !ok {
+		return
+	}
+	relTo, ok := g.relative(to)
+	if !ok {
+		return
+	}
+	if g.edges[relFrom] == nil {
+		g.edges[relFrom] = make(map[string]bool)
+	}
+	g.edges[relFrom][relTo] = true
+}
+
+// relative converts the path to the slash-separated path relative to the repository root
+func (g *Graph) relative(path string) (string, bool) {
+	root, err := filepath.Abs(g.repoRoot)
+	if err != nil {
+		return "", false
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// targets returns the documents the node links to.
+// A link to a directory points to its README.md, because that's what GitHub renders.
+func (g *Graph) targets(node string) []string {
+	result := make([]string, 0, len(g.edges[node]))
+	for to := range g.edges[node] {
+		switch {
+		case g.nodes[to]:
+			result = append(result, to)
+		case g.nodes[joinSlash(to, "README.md")]:
+			result = append(result, joinSlash(to, "README.md"))
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+func joinSlash(dir, file string) string {
+	if dir == "." || dir == "" {
+		return file
+	}
+	return dir + "/" + file
+}
+
+// Orphans returns the documents which are not reachable from the entry points.
+// Entry points which are not in the graph are ignored.
+func (g *Graph) Orphans() []string {
+	reachable := make(map[string]bool)
+	queue := make([]string, 0, len(g.entryPoints))
+	for _, e := range g.entryPoints {
+		if g.nodes[e] && !reachable[e] {
+			reachable[e] = true
+			queue = append(queue, e)
+		}
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, to := range g.targets(node) {
+			if !reachable[to] {
+				reachable[to] = true
+				queue = append(queue, to)
+			}
+		}
+	}
+
+	var orphans []string
+	for node := range g.nodes {
+		if !reachable[node] {
+			orphans = append(orphans, node)
+		}
+	}
+	slices.Sort(orphans)
+	return orphans
+}
+
+// DeadEnds returns the documents which don't link to any other document
+func (g *Graph) DeadEnds() []string {
+	var deadEnds []string
+	for node := range g.nodes {
+		if len(g.targets(node)) == 0 {
+			deadEnds = append(deadEnds, node)
+		}
+	}
+	slices.Sort(deadEnds)
+	return deadEnds
+}
+
+// Export writes the graph in the given format (dot or mermaid). Orphans are highlighted.
+func (g *Graph) Export(w io.Writer, format string) error {
+	orphans := make(map[string]bool)
+	for _, o := range g.Orphans() {
+		orphans[o] = true
+	}
+
+	nodes := make([]string, 0, len(g.nodes))
+	for node := range g.nodes {
+		nodes = append(nodes, node)
+	}
+	slices.Sort(nodes)
+
+	var sb strings.Builder
+	switch format {
+	case FormatDOT:
+		sb.WriteString("digraph docs {\n")
+		for _, node := range nodes {
+			if orphans[node] {
+				fmt.Fprintf(&sb, "  %q [color=red];\n", node)
+			} else {
+				fmt.Fprintf(&sb, "  %q;\n", node)
+			}
+		}
+		for _, node := range nodes {
+			for _, to := range g.targets(node) {
+				fmt.Fprintf(&sb, "  %q -> %q;\n", node, to)
+			}
+		}
+		sb.WriteString("}\n")
+	case FormatMermaid:
+		ids := make(map[string]string, len(nodes))
+		sb.WriteString("graph LR\n")
+		for i, node := range nodes {
+			ids[node] = fmt.Sprintf("n%d", i)
+			fmt.Fprintf(&sb, "  %s[\"%s\"]\n", ids[node], strings.ReplaceAll(node, `"`, "#quot;"))
+		}
+		for _, node := range nodes {
+			for _, to := range g.targets(node) {
+				fmt.Fprintf(&sb, "  %s --> %s\n", ids[node], ids[to])
+			}
+		}
+		if len(orphans) > 0 {
+			sb.WriteString("  classDef orphan stroke:#f00\n")
+			for _, node := range nodes {
+				if orphans[node] {
+					fmt.Fprintf(&sb, "  class %s orphan\n", ids[node])
+				}
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported graph format '%s'", format)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// ExportToFile writes the graph to the file in the given format
+func (g *Graph) ExportToFile(path, format string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = g.Export(f, format); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,134 @@
+package docgraph
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTestGraph builds the following graph in the /repo:
+//
+//	README.md -> docs/guide.md -> docs/api/README.md (via the link to the directory)
+//	docs/index.md -> docs/guide.md
+//	docs/old.md -> docs/guide.md
+//	docs/draft.md
+func newTestGraph(entryPoints []string) *Graph {
+	root := filepath.FromSlash("/repo")
+	g := New(root, entryPoints)
+	for _, node := range []string{"README.md", "docs/index.md", "docs/guide.md", "docs/api/README.md", "docs/old.md", "docs/draft.md"} {
+		g.AddNode(filepath.Join(root, node))
+	}
+	g.AddEdge(filepath.Join(root, "README.md"), filepath.Join(root, "docs/guide.md"))
+	g.AddEdge(filepath.Join(root, "README.md"), filepath.Join(root, "LICENSE"))
+	g.AddEdge(filepath.Join(root, "docs/index.md"), filepath.Join(root, "docs/guide.md"))
+	g.AddEdge(filepath.Join(root, "docs/guide.md"), filepath.Join(root, "docs/api"))
+	g.AddEdge(filepath.Join(root, "docs/old.md"), filepath.Join(root, "docs/guide.md"))
+	// links outside the repository are ignored
+	g.AddEdge(filepath.Join(root, "docs/draft.md"), filepath.FromSlash("/other-repo/README.md"))
+	return g
+}
+
+func TestGraph_Orphans(t *testing.T) {
+	tests := []struct {
+		name        string
+		entryPoints []string
+		want        []string
+	}{
+		{
+			name:        "default entry points",
+			entryPoints: []string{"README.md", "docs/index.md"},
+			want:        []string{"docs/draft.md", "docs/old.md"},
+		},
+		{
+			name:        "entry points with the leading slash",
+			entryPoints: []string{"/README.md"},
+			want:        []string{"docs/draft.md", "docs/index.md", "docs/old.md"},
+		},
+		{
+			name:        "missing entry point makes everything orphan",
+			entryPoints: []string{"index.md"},
+			want:        []string{"README.md", "docs/api/README.md", "docs/draft.md", "docs/guide.md", "docs/index.md", "docs/old.md"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGraph(tt.entryPoints)
+			if got := g.Orphans(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Orphans() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraph_DeadEnds(t *testing.T) {
+	g := newTestGraph([]string{"README.md"})
+	want := []string{"docs/api/README.md", "docs/draft.md"}
+	if got := g.DeadEnds(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DeadEnds() = %v, want %v", got, want)
+	}
+}
+
+func TestGraph_Export(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "dot",
+			format: FormatDOT,
+			want: `digraph docs {
+  "README.md";
+  "docs/api/README.md";
+  "docs/draft.md" [color=red];
+  "docs/guide.md";
+  "docs/index.md";
+  "docs/old.md" [color=red];
+  "README.md" -> "docs/guide.md";
+  "docs/guide.md" -> "docs/api/README.md";
+  "docs/index.md" -> "docs/guide.md";
+  "docs/old.md" -> "docs/guide.md";
+}
+`,
+		},
+		{
+			name:   "mermaid",
+			format: FormatMermaid,
+			want: `graph LR
+  n0["README.md"]
+  n1["docs/api/README.md"]
+  n2["docs/draft.md"]
+  n3["docs/guide.md"]
+  n4["docs/index.md"]
+  n5["docs/old.md"]
+  n0 --> n3
+  n3 --> n1
+  n4 --> n3
+  n5 --> n3
+  classDef orphan stroke:#f00
+  class n2 orphan
+  class n5 orphan
+`,
+		},
+		{
+			name:    "unsupported format",
+			format:  "svg",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGraph([]string{"README.md", "docs/index.md"})
+			var sb strings.Builder
+			err := g.Export(&sb, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Export() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := sb.String(); got != tt.want {
+				t.Errorf("Export():\n got: %s\nwant: %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrLFSPointer = errors.New("points to a Git LFS pointer, the file content is not present")
+
+type LFSPointerError struct {
+	Link string
+}
+
+func (e LFSPointerError) Error() string {
+	return fmt.Sprintf("%s. Incorrect link: '%s'",
+		ErrLFSPointer.Error(), e.Link)
+}
+
+func (e LFSPointerError) Is(target error) bool { return target == ErrLFSPointer }
+
+func NewLFSPointer(link string) error {
+	return LFSPointerError{Link: link}
+}
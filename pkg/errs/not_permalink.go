@@ -0,0 +1,30 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrNotPermalink = errors.New("pinned to a branch, not to a commit")
+
+type NotPermalinkError struct {
+	Link   string
+	Branch string
+	// Permalink is the link pinned to the current commit of the branch, empty if it is not resolved
+	Permalink string
+}
+
+func (e NotPermalinkError) Error() string {
+	if e.Permalink == "" {
+		return fmt.Sprintf("%s '%s'. Incorrect link: '%s'",
+			ErrNotPermalink.Error(), e.Branch, e.Link)
+	}
+	return fmt.Sprintf("%s '%s'. Incorrect link: '%s', permalink: '%s'",
+		ErrNotPermalink.Error(), e.Branch, e.Link, e.Permalink)
+}
+
+func (e NotPermalinkError) Is(target error) bool { return target == ErrNotPermalink }
+
+func NewNotPermalink(link, branch, permalink string) error {
+	return NotPermalinkError{Link: link, Branch: branch, Permalink: permalink}
+}
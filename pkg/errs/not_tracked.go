@@ -0,0 +1,23 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrNotTracked = errors.New("exists locally, but is not tracked by git (untracked or ignored)")
+
+type NotTrackedError struct {
+	Link string
+}
+
+func (e NotTrackedError) Error() string {
+	return fmt.Sprintf("%s. Incorrect link: '%s'",
+		ErrNotTracked.Error(), e.Link)
+}
+
+func (e NotTrackedError) Is(target error) bool { return target == ErrNotTracked }
+
+func NewNotTracked(link string) error {
+	return NotTrackedError{Link: link}
+}
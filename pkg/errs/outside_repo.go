@@ -0,0 +1,23 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrOutsideRepo = errors.New("points outside the repository root, it won't render on GitHub")
+
+type OutsideRepoError struct {
+	Link string
+}
+
+func (e OutsideRepoError) Error() string {
+	return fmt.Sprintf("%s. Incorrect link: '%s'",
+		ErrOutsideRepo.Error(), e.Link)
+}
+
+func (e OutsideRepoError) Is(target error) bool { return target == ErrOutsideRepo }
+
+func NewOutsideRepo(link string) error {
+	return OutsideRepoError{Link: link}
+}
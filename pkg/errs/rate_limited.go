@@ -0,0 +1,23 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrRateLimited = errors.New("unverified (rate limited)")
+
+type RateLimitedError struct {
+	Link string
+}
+
+func (e RateLimitedError) Error() string {
+	return fmt.Sprintf("%s. Link: '%s'",
+		ErrRateLimited.Error(), e.Link)
+}
+
+func (e RateLimitedError) Is(target error) bool { return target == ErrRateLimited }
+
+func NewRateLimited(link string) error {
+	return RateLimitedError{Link: link}
+}
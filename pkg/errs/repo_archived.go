@@ -0,0 +1,25 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrRepoArchived = errors.New("repository is archived")
+
+type RepoArchivedError struct {
+	Link string
+	// FullName is the canonical name of the repository, e.g. 'your-ko/link-validator'
+	FullName string
+}
+
+func (e RepoArchivedError) Error() string {
+	return fmt.Sprintf("%s, the canonical name is '%s'. Probably stale link: '%s'",
+		ErrRepoArchived.Error(), e.FullName, e.Link)
+}
+
+func (e RepoArchivedError) Is(target error) bool { return target == ErrRepoArchived }
+
+func NewRepoArchived(link, fullName string) error {
+	return RepoArchivedError{Link: link, FullName: fullName}
+}
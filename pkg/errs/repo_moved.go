@@ -0,0 +1,25 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrRepoMoved = errors.New("repository has been renamed or transferred")
+
+type RepoMovedError struct {
+	Link string
+	// FullName is the canonical name of the repository, e.g. 'your-ko/link-validator'
+	FullName string
+}
+
+func (e RepoMovedError) Error() string {
+	return fmt.Sprintf("%s, the canonical name is '%s'. Outdated link: '%s'",
+		ErrRepoMoved.Error(), e.FullName, e.Link)
+}
+
+func (e RepoMovedError) Is(target error) bool { return target == ErrRepoMoved }
+
+func NewRepoMoved(link, fullName string) error {
+	return RepoMovedError{Link: link, FullName: fullName}
+}
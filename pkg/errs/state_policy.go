@@ -0,0 +1,27 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrStatePolicy = errors.New("violates the state policy")
+
+type StatePolicyError struct {
+	Link string
+	// State describes the state of the linked issue or pull request, e.g. 'issue is closed as not planned'
+	State string
+	// Fail is true if any of the violated policies fails the validation, otherwise it is a warning
+	Fail bool
+}
+
+func (e StatePolicyError) Error() string {
+	return fmt.Sprintf("%s: %s. Link: '%s'",
+		ErrStatePolicy.Error(), e.State, e.Link)
+}
+
+func (e StatePolicyError) Is(target error) bool { return target == ErrStatePolicy }
+
+func NewStatePolicy(link, state string, fail bool) error {
+	return StatePolicyError{Link: link, State: state, Fail: fail}
+}
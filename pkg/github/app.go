@@ -0,0 +1,169 @@
+package github
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"link-validator/pkg/config"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// appJWTTTL is the lifetime of the app JWT, GitHub allows at most 10 minutes
+	appJWTTTL = 9 * time.Minute
+	// appTokenRefresh defines how long before the expiry the installation token is refreshed
+	appTokenRefresh = 5 * time.Minute
+)
+
+// appTransport authenticates the requests as a GitHub App installation.
+// Installation tokens expire after an hour, the transport mints a new one shortly before the expiry.
+type appTransport struct {
+	base           http.RoundTripper
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+	// baseURL is the REST API url, e.g. 'https://api.github.com/'
+	baseURL string
+	now     func() time.Time
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// newAppTransport loads the private key of the app and returns the transport authenticating the requests
+func newAppTransport(base http.RoundTripper, app config.GitHubAppConfig, baseURL string) (*appTransport, error) {
+	key, err := loadAppKey(app)
+	if err != nil {
+		return nil, err
+	}
+	return &appTransport{
+		base:           base,
+		appID:          app.AppID,
+		installationID: app.InstallationID,
+		key:            key,
+		baseURL:        baseURL,
+		now:            time.Now,
+	}, nil
+}
+
+// loadAppKey reads the private key of the app either from the config, the environment variable or the file
+func loadAppKey(app config.GitHubAppConfig) (*rsa.PrivateKey, error) {
+	data := []byte(app.PrivateKey)
+	switch {
+	case app.PrivateKeyEnv != "":
+		data = []byte(config.GetEnv(app.PrivateKeyEnv, ""))
+	case app.PrivateKeyFile != "":
+		var err error
+		if data, err = os.ReadFile(app.PrivateKeyFile); err != nil {
+			return nil, fmt.Errorf("can't read GitHub App private key: %w", err)
+		}
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("GitHub App private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse GitHub App private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("GitHub App private key is not an RSA key")
+	}
+	return key, nil
+}
+
+func (t *appTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.installationToken(req)
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "token "+token)
+	return t.transport().RoundTrip(req)
+}
+
+func (t *appTransport) transport() http.RoundTripper {
+	if t.base == nil {
+		return http.DefaultTransport
+	}
+	return t.base
+}
+
+// installationToken returns the cached installation token or mints a new one if it is about to expire
+//
+// GitHub API docs: https://docs.github.com/rest/apps/apps#create-an-installation-access-token-for-an-app
+//
+//meta:operation POST /app/installations/{installation_id}/access_tokens
+func (t *appTransport) installationToken(orig *http.Request) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.token != "" && t.now().Add(appTokenRefresh).Before(t.expiresAt) {
+		return t.token, nil
+	}
+
+	jwt, err := t.jwt()
+	if err != nil {
+		return "", err
+	}
+	tokenURL := fmt.Sprintf("%sapp/installations/%d/access_tokens", t.baseURL, t.installationID)
+	req, err := http.NewRequestWithContext(orig.Context(), http.MethodPost, tokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := t.transport().RoundTrip(req)
+	if err != nil {
+		return "", fmt.Errorf("can't get GitHub App installation token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("can't get GitHub App installation token: %s", resp.Status)
+	}
+
+	var body struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("can't decode GitHub App installation token: %w", err)
+	}
+	t.token, t.expiresAt = body.Token, body.ExpiresAt
+	return t.token, nil
+}
+
+// jwt returns the JSON Web Token signed by the app private key, which is used to mint installation tokens
+func (t *appTransport) jwt() (string, error) {
+	now := t.now()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]any{
+		// issued in the past to allow for clock drift
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(appJWTTTL).Unix(),
+		"iss": strconv.FormatInt(t.appID, 10),
+	})
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, t.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("can't sign GitHub App JWT: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,153 @@
+package github
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"link-validator/pkg/config"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_appTransport(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	now := time.Now()
+	mints := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v3/app/installations/2/access_tokens":
+			if err := verifyJWT(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), &key.PublicKey); err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			mints++
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprintf(w, `{"token": "ghs_%d", "expires_at": %q}`, mints, now.Add(time.Hour).Format(time.RFC3339))
+		case r.Header.Get("Authorization") == fmt.Sprintf("token ghs_%d", mints):
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	keyFile := filepath.Join(t.TempDir(), "app.pem")
+	pkcs8, _ := x509.MarshalPKCS8PrivateKey(key)
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	app := config.GitHubAppConfig{AppID: 1, InstallationID: 2, PrivateKeyFile: keyFile}
+	transport, err := newAppTransport(nil, app, server.URL+"/api/v3/")
+	if err != nil {
+		t.Fatalf("newAppTransport() error = %v", err)
+	}
+	transport.now = func() time.Time { return now }
+	c := &http.Client{Transport: transport}
+
+	get := func() {
+		t.Helper()
+		resp, err := c.Get(server.URL + "/api/v3/repos/your-ko/link-validator")
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Get() status = %d, want 200", resp.StatusCode)
+		}
+	}
+
+	get()
+	get()
+	if mints != 1 {
+		t.Errorf("the token is minted %d times, want 1", mints)
+	}
+	// the token is refreshed before it expires
+	now = now.Add(56 * time.Minute)
+	get()
+	if mints != 2 {
+		t.Errorf("the token is minted %d times after the refresh, want 2", mints)
+	}
+}
+
+func Test_loadAppKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	pkcs1 := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	t.Setenv("APP_KEY", pkcs1)
+
+	tests := []struct {
+		name    string
+		app     config.GitHubAppConfig
+		wantErr string
+	}{
+		{name: "private key", app: config.GitHubAppConfig{PrivateKey: pkcs1}},
+		{name: "private key from env", app: config.GitHubAppConfig{PrivateKeyEnv: "APP_KEY"}},
+		{name: "empty env", app: config.GitHubAppConfig{PrivateKeyEnv: "MISSING_APP_KEY"}, wantErr: "GitHub App private key is not PEM encoded"},
+		{name: "missing file", app: config.GitHubAppConfig{PrivateKeyFile: "missing.pem"}, wantErr: "can't read GitHub App private key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadAppKey(tt.app)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("loadAppKey() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadAppKey() error = %v", err)
+			}
+			if !got.Equal(key) {
+				t.Errorf("loadAppKey() returned another key")
+			}
+		})
+	}
+}
+
+// verifyJWT checks the signature and the claims of the app JWT
+func verifyJWT(token string, key *rsa.PublicKey) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("malformed JWT")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return err
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return err
+	}
+	var claims struct {
+		Iss string `json:"iss"`
+		Iat int64  `json:"iat"`
+		Exp int64  `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return err
+	}
+	if claims.Iss != "1" || claims.Exp-claims.Iat > int64((10*time.Minute).Seconds()) {
+		return fmt.Errorf("unexpected claims %+v", claims)
+	}
+	return nil
+}
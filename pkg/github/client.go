@@ -2,17 +2,24 @@ package github
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
 
 	"github.com/google/go-github/v88/github"
 )
 
 type client interface {
+	get(ctx context.Context, path string) (*github.Response, error)
+	graphQL(ctx context.Context, query string, variables map[string]any) (json.RawMessage, error)
 	getRepository(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error)
 	getContents(ctx context.Context, owner, repo, ref, path string) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error)
 	getCommit(ctx context.Context, owner, repo, sha string, opts *github.ListOptions) (*github.RepositoryCommit, *github.Response, error)
+	getBranch(ctx context.Context, owner, repo, branch string) (*github.Branch, *github.Response, error)
+	listMatchingRefs(ctx context.Context, owner, repo, ref string) ([]*github.Reference, *github.Response, error)
 	compareCommits(ctx context.Context, owner, repo, base, head string, opts *github.ListOptions) (*github.CommitsComparison, *github.Response, error)
 	getPR(ctx context.Context, owner, repo string, number int) (*github.PullRequest, *github.Response, error)
 	listCommits(ctx context.Context, owner, repo string, number int, opts *github.ListOptions) ([]*github.RepositoryCommit, *github.Response, error)
+	listPRFiles(ctx context.Context, owner, repo string, number int, opts *github.ListOptions) ([]*github.CommitFile, *github.Response, error)
 	getPRComment(ctx context.Context, owner, repo string, commentID int64) (*github.PullRequestComment, *github.Response, error)
 	getIssueComment(ctx context.Context, owner, repo string, commentID int64) (*github.IssueComment, *github.Response, error)
 	getMilestone(ctx context.Context, owner, repo string, number int) (*github.Milestone, *github.Response, error)
@@ -25,7 +32,7 @@ type client interface {
 	getIssue(ctx context.Context, owner, repo string, number int) (*github.Issue, *github.Response, error)
 	getLatestRelease(ctx context.Context, owner, repo string) (*github.RepositoryRelease, *github.Response, error)
 	getReleaseByTag(ctx context.Context, owner, repo, tag string) (*github.RepositoryRelease, *github.Response, error)
-	listLabels(ctx context.Context, owner, repo string, opts *github.ListOptions) ([]*github.Label, *github.Response, error)
+	getLabel(ctx context.Context, owner, repo, name string) (*github.Label, *github.Response, error)
 	getOrganization(ctx context.Context, org string) (*github.Organization, *github.Response, error)
 	getGist(ctx context.Context, gistID string) (*github.Gist, *github.Response, error)
 	getGistRevision(ctx context.Context, gistID, sha string) (*github.Gist, *github.Response, error)
@@ -37,7 +44,17 @@ type client interface {
 }
 
 type wrapper struct {
-	client *github.Client
+	client  *github.Client
+	limiter *rateLimiter
+}
+
+// get requests the path relative to the REST API root, e.g. 'repos/owner/repo/issues/1'
+func (w *wrapper) get(ctx context.Context, path string) (*github.Response, error) {
+	req, err := w.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return w.client.Do(req, nil)
 }
 
 func (w *wrapper) getRepository(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error) {
@@ -52,6 +69,14 @@ func (w *wrapper) getCommit(ctx context.Context, owner, repo, sha string, opts *
 	return w.client.Repositories.GetCommit(ctx, owner, repo, sha, opts)
 }
 
+func (w *wrapper) getBranch(ctx context.Context, owner, repo, branch string) (*github.Branch, *github.Response, error) {
+	return w.client.Repositories.GetBranch(ctx, owner, repo, branch, 1)
+}
+
+func (w *wrapper) listMatchingRefs(ctx context.Context, owner, repo, ref string) ([]*github.Reference, *github.Response, error) {
+	return w.client.Git.ListMatchingRefs(ctx, owner, repo, ref)
+}
+
 func (w *wrapper) compareCommits(ctx context.Context, owner, repo, base, head string, opts *github.ListOptions) (*github.CommitsComparison, *github.Response, error) {
 	return w.client.Repositories.CompareCommits(ctx, owner, repo, base, head, opts)
 }
@@ -60,6 +85,10 @@ func (w *wrapper) listCommits(ctx context.Context, owner, repo string, number in
 	return w.client.PullRequests.ListCommits(ctx, owner, repo, number, opts)
 }
 
+func (w *wrapper) listPRFiles(ctx context.Context, owner, repo string, number int, opts *github.ListOptions) ([]*github.CommitFile, *github.Response, error) {
+	return w.client.PullRequests.ListFiles(ctx, owner, repo, number, opts)
+}
+
 func (w *wrapper) getPR(ctx context.Context, owner, repo string, number int) (*github.PullRequest, *github.Response, error) {
 	return w.client.PullRequests.Get(ctx, owner, repo, number)
 }
@@ -112,8 +141,8 @@ func (w *wrapper) getReleaseByTag(ctx context.Context, owner, repo, tag string)
 	return w.client.Repositories.GetReleaseByTag(ctx, owner, repo, tag)
 }
 
-func (w *wrapper) listLabels(ctx context.Context, owner, repo string, opts *github.ListOptions) ([]*github.Label, *github.Response, error) {
-	return w.client.Issues.ListLabels(ctx, owner, repo, opts)
+func (w *wrapper) getLabel(ctx context.Context, owner, repo, name string) (*github.Label, *github.Response, error) {
+	return w.client.Issues.GetLabel(ctx, owner, repo, name)
 }
 
 func (w *wrapper) getOrganization(ctx context.Context, org string) (*github.Organization, *github.Response, error) {
@@ -0,0 +1,122 @@
+package github
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"link-validator/pkg/errs"
+	"link-validator/pkg/regex"
+	"log/slog"
+	"strconv"
+
+	"github.com/google/go-github/v88/github"
+)
+
+// checkDiffAnchor checks that the '#diff-<hash>' anchor points to a file of the diff.
+// The hash is sha256 (md5 in older links) of the file path. The optional line suffix, e.g. 'R31' for the new version
+// of the file or 'L31' for the old one, must be a part of the hunks shown in the diff, unless the patch is too large
+// to be returned by the API. Anchors in unknown formats are not validated, the files aren't listed then.
+func checkDiffAnchor(fragment string, listFiles func() ([]*github.CommitFile, error)) error {
+	m := regex.DiffAnchor.FindStringSubmatch(fragment)
+	if m == nil {
+		slog.Debug("github: unknown diff anchor format, skipping", slog.String("anchor", fragment))
+		return nil
+	}
+	files, err := listFiles()
+	if err != nil {
+		return err
+	}
+	var file *github.CommitFile
+	for _, f := range files {
+		if diffHash(f.GetFilename(), len(m[1])) == m[1] || (f.GetPreviousFilename() != "" && diffHash(f.GetPreviousFilename(), len(m[1])) == m[1]) {
+			file = f
+			break
+		}
+	}
+	if file == nil {
+		return errs.NewNotFoundMessage(fmt.Sprintf("diff anchor '#%s' doesn't match any file of the diff", fragment))
+	}
+	for _, line := range m[2:] {
+		if line != "" && !inPatch(file.GetPatch(), line) {
+			return errs.NewNotFoundMessage(fmt.Sprintf("line '%s' is not a part of the diff of '%s'", line, file.GetFilename()))
+		}
+	}
+	return nil
+}
+
+// diffHash returns the hash GitHub uses in diff anchors, sha256 or md5 depending on the length of the anchor hash
+func diffHash(path string, length int) string {
+	if length == md5.Size*2 {
+		sum := md5.Sum([]byte(path))
+		return hex.EncodeToString(sum[:])
+	}
+	sum := sha256.Sum256([]byte(path))
+	return hex.EncodeToString(sum[:])
+}
+
+// inPatch checks that the line, e.g. 'R31', is in one of the hunks of the patch. The empty patch can't be checked
+func inPatch(patch, line string) bool {
+	if patch == "" {
+		return true
+	}
+	n, _ := strconv.Atoi(line[1:])
+	for _, hunk := range regex.DiffHunk.FindAllStringSubmatch(patch, -1) {
+		start, length := hunk[1], hunk[2]
+		if line[0] == 'R' {
+			start, length = hunk[3], hunk[4]
+		}
+		first, _ := strconv.Atoi(start)
+		count := 1
+		if length != "" {
+			count, _ = strconv.Atoi(length)
+		}
+		if n >= first && n < first+count {
+			return true
+		}
+	}
+	return false
+}
+
+// listPRFiles returns all the files changed by the pull request
+//
+// GitHub API docs: https://docs.github.com/rest/pulls/pulls#list-pull-requests-files
+//
+//meta:operation GET /repos/{owner}/{repo}/pulls/{pull_number}/files
+func listPRFiles(ctx context.Context, c client, owner, repo string, number int) ([]*github.CommitFile, error) {
+	var files []*github.CommitFile
+	opts := &github.ListOptions{PerPage: perPage}
+	for {
+		page, resp, err := c.listPRFiles(ctx, owner, repo, number, opts)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, page...)
+		if resp == nil || resp.NextPage == 0 {
+			return files, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// listCommitFiles returns all the files changed by the commit, the files of large commits are paginated
+//
+// GitHub API docs: https://docs.github.com/rest/commits/commits#get-a-commit
+//
+//meta:operation GET /repos/{owner}/{repo}/commits/{ref}
+func listCommitFiles(ctx context.Context, c client, owner, repo, sha string) ([]*github.CommitFile, error) {
+	var files []*github.CommitFile
+	opts := &github.ListOptions{PerPage: perPage}
+	for {
+		commit, resp, err := c.getCommit(ctx, owner, repo, sha, opts)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, commit.Files...)
+		if resp == nil || resp.NextPage == 0 {
+			return files, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
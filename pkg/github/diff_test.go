@@ -0,0 +1,78 @@
+package github
+
+import (
+	"errors"
+	"link-validator/pkg/errs"
+	"testing"
+
+	"github.com/google/go-github/v88/github"
+)
+
+func Test_checkDiffAnchor(t *testing.T) {
+	// sha256 and md5 of 'README.md'
+	readme := "b335630551682c19a781afebcf4d07bf978fb1f8ac04c6bf87428ed5106870f5"
+	readmeMD5 := "04c6e90faac2675aa89e2176d2eec7d8"
+	files := []*github.CommitFile{
+		{Filename: github.Ptr("pkg/github/handlers.go")},
+		{
+			Filename:         github.Ptr("README.md"),
+			PreviousFilename: github.Ptr("docs/README.md"),
+			Patch:            github.Ptr("@@ -10,7 +10,8 @@ func a() {\n context\n-old\n+new\n+new\n@@ -40 +41,2 @@\n-old\n+new\n+new"),
+		},
+	}
+
+	tests := []struct {
+		name      string
+		fragment  string
+		wantErr   string
+		wantCalls int
+	}{
+		{name: "file", fragment: "diff-" + readme, wantCalls: 1},
+		{name: "file in the older md5 format", fragment: "diff-" + readmeMD5, wantCalls: 1},
+		{name: "line of the new version", fragment: "diff-" + readme + "R17", wantCalls: 1},
+		{name: "line of the old version", fragment: "diff-" + readme + "L40", wantCalls: 1},
+		{name: "range of lines", fragment: "diff-" + readme + "R41-R42", wantCalls: 1},
+		{
+			name:      "line outside the hunks",
+			fragment:  "diff-" + readme + "R18",
+			wantErr:   "line 'R18' is not a part of the diff of 'README.md'",
+			wantCalls: 1,
+		},
+		{
+			name:      "file without patch",
+			fragment:  "diff-06727a65b099713c01f0dc250f0a325fcaa317b91e10b15624e60958140530fcR1000",
+			wantCalls: 1,
+		},
+		{
+			name:      "file is not a part of the diff",
+			fragment:  "diff-48192d841d01a270fcd26b6e06f1e886333860b7f1ce32ae5758338d3c6551f7",
+			wantErr:   "diff anchor '#diff-48192d841d01a270fcd26b6e06f1e886333860b7f1ce32ae5758338d3c6551f7' doesn't match any file of the diff",
+			wantCalls: 1,
+		},
+		{name: "unknown format isn't validated", fragment: "diff-aaa"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := checkDiffAnchor(tt.fragment, func() ([]*github.CommitFile, error) {
+				calls++
+				return files, nil
+			})
+			if calls != tt.wantCalls {
+				t.Errorf("the files are listed %d times, want %d", calls, tt.wantCalls)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkDiffAnchor() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, errs.ErrNotFound) {
+				t.Errorf("expected \n errors.Is(err, %v) to be true; \n got err=%v", errs.ErrNotFound, err)
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("checkDiffAnchor() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"link-validator/pkg/errs"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v88/github"
+)
+
+const discussionQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) { discussion(number: $number) { id } }
+}`
+
+// discussionCommentsQuery pages through the comments of the discussion with their replies
+const discussionCommentsQuery = `query($owner: String!, $name: String!, $number: Int!, $cursor: String) {
+  repository(owner: $owner, name: $name) {
+    discussion(number: $number) {
+      comments(first: 100, after: $cursor) {
+        pageInfo { hasNextPage endCursor }
+        nodes { id databaseId replies(first: 100) { pageInfo { hasNextPage endCursor } nodes { databaseId } } }
+      }
+    }
+  }
+}`
+
+// discussionRepliesQuery pages through the replies of the comment, the first page comes with discussionCommentsQuery
+const discussionRepliesQuery = `query($id: ID!, $cursor: String) {
+  node(id: $id) {
+    ... on DiscussionComment {
+      replies(first: 100, after: $cursor) { pageInfo { hasNextPage endCursor } nodes { databaseId } }
+    }
+  }
+}`
+
+const discussionCategoryQuery = `query($owner: String!, $name: String!, $slug: String!) {
+  repository(owner: $owner, name: $name) { discussionCategory(slug: $slug) { id } }
+}`
+
+// handleDiscussions validates discussions, their comments and categories via GraphQL, discussions aren't available via REST API.
+// Comment anchors look like '#discussioncomment-<id>', the id is the database id of the comment or the reply.
+// GraphQL requires a token, without it the link is validated via HTTP.
+//
+// GitHub API docs: https://docs.github.com/graphql/guides/using-the-graphql-api-for-discussions
+func handleDiscussions(ctx context.Context, c client, httpClient *http.Client, gh *ghURL) error {
+	err := validateDiscussion(ctx, c, gh)
+	if isUnauthorized(err) {
+		slog.Debug("github: GraphQL requires a token, delegating to HTTP validator", slog.String("url", gh.url))
+		return handleHttp(ctx, c, httpClient, gh)
+	}
+	return err
+}
+
+func validateDiscussion(ctx context.Context, c client, gh *ghURL) error {
+	if gh.ref == "categories" && gh.path != "" {
+		var data struct {
+			Repository *struct {
+				DiscussionCategory *struct{} `json:"discussionCategory"`
+			} `json:"repository"`
+		}
+		err := queryGraphQL(ctx, c, discussionCategoryQuery, map[string]any{"owner": gh.owner, "name": gh.repo, "slug": gh.path}, &data)
+		if err != nil {
+			return err
+		}
+		if data.Repository == nil || data.Repository.DiscussionCategory == nil {
+			return errs.NewNotFoundMessage(fmt.Sprintf("discussion category '%s' not found", gh.path))
+		}
+		return nil
+	}
+
+	number, err := strconv.Atoi(gh.ref)
+	if err != nil {
+		// the list of discussions, 'new' and other pages of the repository
+		return handleRepoExist(ctx, c, gh.owner, gh.repo, "", "", "")
+	}
+
+	commentID, isComment := strings.CutPrefix(gh.anchor, "discussioncomment-")
+	if !isComment {
+		var data struct {
+			Repository *struct {
+				Discussion *struct{} `json:"discussion"`
+			} `json:"repository"`
+		}
+		err = queryGraphQL(ctx, c, discussionQuery, map[string]any{"owner": gh.owner, "name": gh.repo, "number": number}, &data)
+		if err != nil {
+			return err
+		}
+		if data.Repository == nil || data.Repository.Discussion == nil {
+			return errs.NewNotFoundMessage(fmt.Sprintf("discussion '%d' not found", number))
+		}
+		return nil
+	}
+
+	id, err := strconv.ParseInt(commentID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid discussion comment id '%s'", commentID)
+	}
+	var cursor *string
+	for {
+		var data struct {
+			Repository *struct {
+				Discussion *struct {
+					Comments struct {
+						PageInfo struct {
+							HasNextPage bool   `json:"hasNextPage"`
+							EndCursor   string `json:"endCursor"`
+						} `json:"pageInfo"`
+						Nodes []struct {
+							ID         string  `json:"id"`
+							DatabaseID int64   `json:"databaseId"`
+							Replies    replies `json:"replies"`
+						} `json:"nodes"`
+					} `json:"comments"`
+				} `json:"discussion"`
+			} `json:"repository"`
+		}
+		variables := map[string]any{"owner": gh.owner, "name": gh.repo, "number": number, "cursor": cursor}
+		if err = queryGraphQL(ctx, c, discussionCommentsQuery, variables, &data); err != nil {
+			return err
+		}
+		if data.Repository == nil || data.Repository.Discussion == nil {
+			return errs.NewNotFoundMessage(fmt.Sprintf("discussion '%d' not found", number))
+		}
+		comments := data.Repository.Discussion.Comments
+		for _, comment := range comments.Nodes {
+			if comment.DatabaseID == id {
+				return nil
+			}
+			found, err := hasReply(ctx, c, comment.ID, comment.Replies, id)
+			if found || err != nil {
+				return err
+			}
+		}
+		if !comments.PageInfo.HasNextPage {
+			break
+		}
+		cursor = &comments.PageInfo.EndCursor
+	}
+	return errs.NewNotFoundMessage(fmt.Sprintf("comment '%s' not found in discussion '%d'", commentID, number))
+}
+
+// replies is a page of the replies to a discussion comment
+type replies struct {
+	PageInfo struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+	Nodes []struct {
+		DatabaseID int64 `json:"databaseId"`
+	} `json:"nodes"`
+}
+
+// hasReply looks for the reply with the database id, starting with the first page and requesting the next ones
+func hasReply(ctx context.Context, c client, commentID string, page replies, id int64) (bool, error) {
+	for {
+		for _, reply := range page.Nodes {
+			if reply.DatabaseID == id {
+				return true, nil
+			}
+		}
+		if !page.PageInfo.HasNextPage {
+			return false, nil
+		}
+		var data struct {
+			Node *struct {
+				Replies replies `json:"replies"`
+			} `json:"node"`
+		}
+		variables := map[string]any{"id": commentID, "cursor": page.PageInfo.EndCursor}
+		if err := queryGraphQL(ctx, c, discussionRepliesQuery, variables, &data); err != nil {
+			return false, err
+		}
+		if data.Node == nil {
+			return false, nil
+		}
+		page = data.Node.Replies
+	}
+}
+
+// queryGraphQL runs the query and decodes its data
+func queryGraphQL(ctx context.Context, c client, query string, variables map[string]any, data any) error {
+	raw, err := c.graphQL(ctx, query, variables)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, data)
+}
+
+// isUnauthorized returns true if the request requires a token, GraphQL API isn't available for anonymous requests
+func isUnauthorized(err error) bool {
+	var gitHubErr *github.ErrorResponse
+	return errors.As(err, &gitHubErr) && gitHubErr.Response != nil && gitHubErr.Response.StatusCode == http.StatusUnauthorized
+}
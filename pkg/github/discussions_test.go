@@ -0,0 +1,176 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"link-validator/pkg/errs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v88/github"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_handleDiscussions(t *testing.T) {
+	resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+	unauthorized := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusUnauthorized}, Message: "Requires authentication"}
+	comments := func(page string) json.RawMessage {
+		return json.RawMessage(`{"repository":{"discussion":{"comments":` + page + `}}}`)
+	}
+
+	tests := []struct {
+		name       string
+		url        string
+		httpStatus int
+		setupMock  func(m *mockclient)
+		wantIs     error
+		wantErr    string
+	}{
+		{
+			name: "discussion exists",
+			url:  "https://github.com/your-ko/link-validator/discussions/3",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().graphQL(mock.Anything, discussionQuery, map[string]any{"owner": "your-ko", "name": "link-validator", "number": 3}).
+					Return(json.RawMessage(`{"repository":{"discussion":{"id":"D_1"}}}`), nil).Once()
+			},
+		},
+		{
+			name: "missing discussion",
+			url:  "https://github.com/your-ko/link-validator/discussions/404",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().graphQL(mock.Anything, discussionQuery, mock.Anything).
+					Return(nil, errs.NewNotFoundMessage("Could not resolve to a Discussion with the number of 404.")).Once()
+			},
+			wantIs:  errs.ErrNotFound,
+			wantErr: "Could not resolve to a Discussion with the number of 404.",
+		},
+		{
+			name: "discussion is null",
+			url:  "https://github.com/your-ko/link-validator/discussions/5",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().graphQL(mock.Anything, discussionQuery, mock.Anything).
+					Return(json.RawMessage(`{"repository":{"discussion":null}}`), nil).Once()
+			},
+			wantIs:  errs.ErrNotFound,
+			wantErr: "discussion '5' not found",
+		},
+		{
+			name: "comment on the second page",
+			url:  "https://github.com/your-ko/link-validator/discussions/3#discussioncomment-200",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().graphQL(mock.Anything, discussionCommentsQuery, map[string]any{"owner": "your-ko", "name": "link-validator", "number": 3, "cursor": (*string)(nil)}).
+					Return(comments(`{"pageInfo":{"hasNextPage":true,"endCursor":"c1"},"nodes":[{"databaseId":100,"replies":{"nodes":[{"databaseId":101}]}}]}`), nil).Once()
+				m.EXPECT().graphQL(mock.Anything, discussionCommentsQuery, mock.MatchedBy(func(v map[string]any) bool {
+					cursor, ok := v["cursor"].(*string)
+					return ok && cursor != nil && *cursor == "c1"
+				})).Return(comments(`{"pageInfo":{"hasNextPage":false},"nodes":[{"databaseId":200,"replies":{"nodes":[]}}]}`), nil).Once()
+			},
+		},
+		{
+			name: "reply to a comment",
+			url:  "https://github.com/your-ko/link-validator/discussions/3#discussioncomment-101",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().graphQL(mock.Anything, discussionCommentsQuery, mock.Anything).
+					Return(comments(`{"pageInfo":{"hasNextPage":false},"nodes":[{"databaseId":100,"replies":{"nodes":[{"databaseId":101}]}}]}`), nil).Once()
+			},
+		},
+		{
+			name: "reply on the second page of the replies",
+			url:  "https://github.com/your-ko/link-validator/discussions/3#discussioncomment-301",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().graphQL(mock.Anything, discussionCommentsQuery, mock.Anything).
+					Return(comments(`{"pageInfo":{"hasNextPage":false},"nodes":[`+
+						`{"id":"DC_1","databaseId":100,"replies":{"pageInfo":{"hasNextPage":true,"endCursor":"r1"},"nodes":[{"databaseId":101}]}}]}`), nil).Once()
+				m.EXPECT().graphQL(mock.Anything, discussionRepliesQuery, map[string]any{"id": "DC_1", "cursor": "r1"}).
+					Return(json.RawMessage(`{"node":{"replies":{"pageInfo":{"hasNextPage":false},"nodes":[{"databaseId":301}]}}}`), nil).Once()
+			},
+		},
+		{
+			name: "missing comment",
+			url:  "https://github.com/your-ko/link-validator/discussions/3#discussioncomment-999",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().graphQL(mock.Anything, discussionCommentsQuery, mock.Anything).
+					Return(comments(`{"pageInfo":{"hasNextPage":false},"nodes":[{"databaseId":100,"replies":{"nodes":[]}}]}`), nil).Once()
+			},
+			wantIs:  errs.ErrNotFound,
+			wantErr: "comment '999' not found in discussion '3'",
+		},
+		{
+			name:    "invalid comment id",
+			url:     "https://github.com/your-ko/link-validator/discussions/3#discussioncomment-abc",
+			wantErr: "invalid discussion comment id 'abc'",
+		},
+		{
+			name: "discussion category",
+			url:  "https://github.com/your-ko/link-validator/discussions/categories/q-a",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().graphQL(mock.Anything, discussionCategoryQuery, map[string]any{"owner": "your-ko", "name": "link-validator", "slug": "q-a"}).
+					Return(json.RawMessage(`{"repository":{"discussionCategory":null}}`), nil).Once()
+			},
+			wantIs:  errs.ErrNotFound,
+			wantErr: "discussion category 'q-a' not found",
+		},
+		{
+			name: "list of discussions",
+			url:  "https://github.com/your-ko/link-validator/discussions",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(&github.Repository{}, resp, nil).Once()
+			},
+		},
+		{
+			name:       "without a token the page is requested via HTTP",
+			url:        "https://github.com/your-ko/link-validator/discussions/3",
+			httpStatus: http.StatusOK,
+			setupMock: func(m *mockclient) {
+				m.EXPECT().graphQL(mock.Anything, discussionQuery, mock.Anything).Return(nil, unauthorized).Once()
+				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(&github.Repository{}, resp, nil).Once()
+			},
+		},
+		{
+			name: "GraphQL error",
+			url:  "https://github.com/your-ko/link-validator/discussions/3",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().graphQL(mock.Anything, discussionQuery, mock.Anything).Return(nil, errors.New("GraphQL error: boom")).Once()
+			},
+			wantErr: "GraphQL error: boom",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				if tt.httpStatus == 0 {
+					t.Errorf("unexpected HTTP request %s", req.URL)
+				}
+				res.WriteHeader(tt.httpStatus)
+				_, _ = res.Write([]byte("<html><body>page</body></html>"))
+			}))
+			defer testServer.Close()
+
+			mockClient := newMockclient(t)
+			if tt.setupMock != nil {
+				tt.setupMock(mockClient)
+			}
+			gh, err := parseUrl(tt.url)
+			if err != nil {
+				t.Fatalf("parseUrl() error = %v", err)
+			}
+			gh.url = testServer.URL
+
+			err = handleDiscussions(context.Background(), mockClient, testServer.Client(), gh)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("handleDiscussions() error = %v", err)
+				}
+				return
+			}
+			if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+				t.Errorf("expected \n errors.Is(err, %v) to be true; \n got err=%v", tt.wantIs, err)
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("handleDiscussions() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// etagCache stores GitHub API responses with their validators (ETag and Last-Modified).
+// Cached requests are sent as conditional requests, GitHub responds with '304 Not Modified' if nothing has changed,
+// and such responses don't count against the primary rate limit.
+// The cache is persisted to the file between runs if the file is configured. Only the entries used during the run
+// and belonging to public repositories are persisted, the file is usually uploaded as a CI cache, see save.
+type etagCache struct {
+	path    string
+	entries map[string]*etagEntry
+	// used are the keys of the entries requested or stored during the run
+	used map[string]bool
+	// public are the visibilities of the repositories by 'host/owner/repo', learned from their API responses
+	public map[string]bool
+	loaded int
+	hits   int
+	dirty  bool
+}
+
+type etagEntry struct {
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"lastModified,omitempty"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+}
+
+// cachedHeaders are the response headers needed to reconstruct the response, e.g. 'Link' is used for pagination
+var cachedHeaders = []string{"Content-Type", "Link"}
+
+// newETagCache creates the cache and loads the entries from the file, if the file exists
+func newETagCache(path string) *etagCache {
+	cache := &etagCache{
+		path:    path,
+		entries: make(map[string]*etagEntry),
+		used:    make(map[string]bool),
+		public:  make(map[string]bool),
+	}
+	if path == "" {
+		return cache
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("github: can't read the cache, starting with an empty one", slog.String("file", path), slog.String("error", err.Error()))
+		}
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache.entries); err != nil {
+		slog.Warn("github: can't parse the cache, starting with an empty one", slog.String("file", path), slog.String("error", err.Error()))
+		cache.entries = make(map[string]*etagEntry)
+	}
+	cache.loaded = len(cache.entries)
+	return cache
+}
+
+// transport returns the transport, which sends conditional requests for the cached responses
+func (c *etagCache) transport(base http.RoundTripper) http.RoundTripper {
+	return &etagTransport{base: base, cache: c}
+}
+
+// save writes the cache to the file. The entries not used during the run are evicted, so the file doesn't grow
+// across runs. The responses of private repositories, users, organizations and gists are never persisted,
+// as well as the responses of the repositories whose visibility is unknown.
+func (c *etagCache) save() error {
+	if c.path == "" {
+		return nil
+	}
+	persisted := make(map[string]*etagEntry)
+	for key, entry := range c.entries {
+		if c.used[key] && c.isPublic(key) {
+			persisted[key] = entry
+		}
+	}
+	if !c.dirty && len(persisted) == c.loaded {
+		return nil
+	}
+	data, err := json.Marshal(persisted)
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(c.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	// write to a temporary file first, so the cache is never left half-written
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("can't write the cache: %w", err)
+	}
+	return os.Rename(tmp, c.path)
+}
+
+type etagTransport struct {
+	base  http.RoundTripper
+	cache *etagCache
+}
+
+func (t *etagTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if req.Method != http.MethodGet {
+		return base.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+	entry, cached := t.cache.entries[key]
+	if cached {
+		t.cache.used[key] = true
+		req = req.Clone(req.Context())
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if cached && resp.StatusCode == http.StatusNotModified {
+		_ = resp.Body.Close()
+		t.cache.hits++
+		// the rate limit headers of the 304 response are kept, the rest is restored from the cache
+		resp.StatusCode = http.StatusOK
+		resp.Status = fmt.Sprintf("%d %s", http.StatusOK, http.StatusText(http.StatusOK))
+		for name, values := range entry.Header {
+			resp.Header[name] = values
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(entry.Body))
+		resp.ContentLength = int64(len(entry.Body))
+		t.cache.learnVisibility(req.URL, entry.Body)
+		return resp, nil
+	}
+
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if resp.StatusCode != http.StatusOK || (etag == "" && lastModified == "") {
+		return resp, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	header := make(http.Header)
+	for _, name := range cachedHeaders {
+		if values := resp.Header.Values(name); len(values) > 0 {
+			header[name] = values
+		}
+	}
+	t.cache.entries[key] = &etagEntry{ETag: etag, LastModified: lastModified, Header: header, Body: body}
+	t.cache.used[key] = true
+	t.cache.dirty = true
+	t.cache.learnVisibility(req.URL, body)
+	return resp, nil
+}
+
+// learnVisibility records the visibility of the repository from its metadata, 'GET /repos/{owner}/{repo}'
+func (c *etagCache) learnVisibility(u *url.URL, body []byte) {
+	repo, rest, ok := repoOf(u)
+	if !ok || rest != "" {
+		return
+	}
+	var metadata struct {
+		Private *bool `json:"private"`
+	}
+	if err := json.Unmarshal(body, &metadata); err != nil || metadata.Private == nil {
+		return
+	}
+	c.public[repo] = !*metadata.Private
+}
+
+// isPublic returns true if the cached url belongs to a repository known to be public
+func (c *etagCache) isPublic(key string) bool {
+	u, err := url.Parse(key)
+	if err != nil {
+		return false
+	}
+	repo, _, ok := repoOf(u)
+	return ok && c.public[repo]
+}
+
+// repoOf returns 'host/owner/repo' of the REST API url under '/repos/{owner}/{repo}' and the rest of its path.
+// GitHub Enterprise urls are prefixed, e.g. '/api/v3/repos/{owner}/{repo}'.
+func repoOf(u *url.URL) (string, string, bool) {
+	_, after, ok := strings.Cut(u.Path, "/repos/")
+	if !ok {
+		return "", "", false
+	}
+	parts := strings.SplitN(after, "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	rest := ""
+	if len(parts) == 3 {
+		rest = parts[2]
+	}
+	return strings.ToLower(u.Host + "/" + parts[0] + "/" + parts[1]), rest, true
+}
@@ -0,0 +1,154 @@
+package github
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func Test_etagTransport(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		switch r.URL.Path {
+		case "/etag":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Link", `<https://api.github.com/next>; rel="next"`)
+		case "/modified":
+			if r.Header.Get("If-Modified-Since") == "Mon, 02 Jan 2006 15:04:05 GMT" {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		}
+		_, _ = w.Write([]byte("body of " + r.URL.Path))
+	}))
+	defer server.Close()
+
+	get := func(cache *etagCache, path string) *http.Response {
+		t.Helper()
+		c := &http.Client{Transport: cache.transport(nil)}
+		resp, err := c.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || string(body) != "body of "+path {
+			t.Fatalf("unexpected response %d %q", resp.StatusCode, body)
+		}
+		return resp
+	}
+
+	cache := newETagCache("")
+	for _, path := range []string{"/etag", "/modified", "/uncacheable", "/etag", "/modified", "/uncacheable"} {
+		get(cache, path)
+	}
+	if cache.hits != 2 {
+		t.Errorf("hits = %d, want 2", cache.hits)
+	}
+	resp := get(cache, "/etag")
+	if resp.Header.Get("Link") == "" || resp.Header.Get("X-RateLimit-Remaining") != "4999" {
+		t.Errorf("headers are not restored: %v", resp.Header)
+	}
+	if requests != 7 {
+		t.Errorf("requests = %d, want 7", requests)
+	}
+}
+
+func Test_etagCache_save(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		switch r.URL.Path {
+		case "/api/v3/repos/your-ko/public":
+			_, _ = w.Write([]byte(`{"private": false}`))
+		case "/api/v3/repos/your-ko/private":
+			_, _ = w.Write([]byte(`{"private": true}`))
+		default:
+			_, _ = w.Write([]byte("body of " + r.URL.Path))
+		}
+	}))
+	defer server.Close()
+
+	cacheFile := filepath.Join(t.TempDir(), "cache", "github.json")
+	run := func(paths ...string) *etagCache {
+		t.Helper()
+		cache := newETagCache(cacheFile)
+		c := &http.Client{Transport: cache.transport(nil)}
+		for _, path := range paths {
+			resp, err := c.Get(server.URL + path)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			_ = resp.Body.Close()
+		}
+		if err := cache.save(); err != nil {
+			t.Fatalf("save() error = %v", err)
+		}
+		return cache
+	}
+	persisted := func() []string {
+		t.Helper()
+		data, err := os.ReadFile(cacheFile)
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+		var entries map[string]*etagEntry
+		if err := json.Unmarshal(data, &entries); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		var paths []string
+		for key := range entries {
+			paths = append(paths, strings.TrimPrefix(key, server.URL))
+		}
+		sort.Strings(paths)
+		return paths
+	}
+
+	run(
+		"/api/v3/repos/your-ko/public",
+		"/api/v3/repos/your-ko/public/contents/README.md",
+		"/api/v3/repos/your-ko/public/contents/CHANGELOG.md",
+		"/api/v3/repos/your-ko/private",
+		"/api/v3/repos/your-ko/private/contents/secret.md",
+		"/api/v3/repos/your-ko/unknown/contents/README.md",
+		"/api/v3/users/your-ko",
+	)
+	want := []string{
+		"/api/v3/repos/your-ko/public",
+		"/api/v3/repos/your-ko/public/contents/CHANGELOG.md",
+		"/api/v3/repos/your-ko/public/contents/README.md",
+	}
+	if got := persisted(); !reflect.DeepEqual(got, want) {
+		t.Errorf("persisted entries mismatch\ngot = %#v\nwant= %#v", got, want)
+	}
+
+	// the visibility is learned from the cached response as well, the entries not used in the run are evicted
+	cache := run("/api/v3/repos/your-ko/public", "/api/v3/repos/your-ko/public/contents/README.md")
+	if cache.hits != 2 {
+		t.Errorf("hits = %d, want 2", cache.hits)
+	}
+	want = []string{
+		"/api/v3/repos/your-ko/public",
+		"/api/v3/repos/your-ko/public/contents/README.md",
+	}
+	if got := persisted(); !reflect.DeepEqual(got, want) {
+		t.Errorf("persisted entries after eviction mismatch\ngot = %#v\nwant= %#v", got, want)
+	}
+}
@@ -0,0 +1,238 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"link-validator/pkg/errs"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// graphQLBatchSize limits the number of objects resolved by a single GraphQL query,
+// GitHub limits the number of nodes a query may request.
+const graphQLBatchSize = 50
+
+// graphQLKey identifies a GitHub object, which can be resolved via GraphQL.
+// kind is one of 'repo', 'issue', 'pull', 'release' and 'discussion', id is the number or the release tag.
+type graphQLKey struct {
+	host  string
+	owner string
+	repo  string
+	kind  string
+	id    string
+}
+
+// graphQLKeyOf returns the key of the object the link points to, if the link can be validated by GraphQL
+// without any further REST calls, e.g. links to issue comments are still validated via REST API.
+func graphQLKeyOf(gh *ghURL) (graphQLKey, bool) {
+	key := graphQLKey{host: strings.ToLower(gh.host), owner: gh.owner, repo: gh.repo}
+	if gh.owner == "" || gh.repo == "" || gh.anchor != "" {
+		return key, false
+	}
+	switch {
+	case handlers[gh.typ].name == "repo-exist":
+		key.kind = "repo"
+	case gh.typ == "issues" && gh.ref == "":
+		key.kind = "repo"
+	case gh.typ == "issues" && gh.path == "" && isNumber(gh.ref):
+		key.kind, key.id = "issue", gh.ref
+	case gh.typ == "pull" && gh.path == "" && isNumber(gh.ref):
+		key.kind, key.id = "pull", gh.ref
+	case gh.typ == "releases" && gh.ref == "tag" && gh.path != "":
+		key.kind, key.id = "release", gh.path
+	case gh.typ == "discussions" && gh.path == "" && isNumber(gh.ref):
+		key.kind, key.id = "discussion", gh.ref
+	default:
+		return key, false
+	}
+	return key, true
+}
+
+// needsState returns true if the state policies apply to the object, which is checked via REST API then
+func (proc *LinkProcessor) needsState(key graphQLKey) bool {
+	switch key.kind {
+	case "issue":
+		return proc.statePolicies.HasIssuePolicies()
+	case "pull":
+		return proc.statePolicies.UnmergedPull != ""
+	}
+	return false
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
+
+// Prefetch resolves the GitHub objects the links point to in batched GraphQL queries.
+// Process uses the results instead of REST calls, the links which weren't resolved are validated via REST API as usual.
+func (proc *LinkProcessor) Prefetch(ctx context.Context, urls []string) {
+	if !proc.graphQL {
+		return
+	}
+	pending := make(map[*wrapper][]graphQLKey)
+	seen := make(map[graphQLKey]bool)
+	for _, u := range urls {
+		gh, err := parseUrl(u)
+		if err != nil {
+			continue
+		}
+		client := proc.clientFor(gh)
+		if client == nil {
+			continue
+		}
+		key, ok := graphQLKeyOf(gh)
+		if !ok || seen[key] || proc.needsState(key) {
+			continue
+		}
+		seen[key] = true
+		pending[client] = append(pending[client], key)
+	}
+
+	for client, keys := range pending {
+		for start := 0; start < len(keys); start += graphQLBatchSize {
+			batch := keys[start:min(start+graphQLBatchSize, len(keys))]
+			resolved, states, err := client.resolve(ctx, batch)
+			if err != nil {
+				slog.Warn("github: GraphQL batch failed, falling back to REST API", slog.String("error", err.Error()))
+				break
+			}
+			for key, exists := range resolved {
+				proc.resolved[key] = exists
+			}
+			// the states are used by checkRepository, the resolved links don't need any REST calls then
+			for key, state := range states {
+				proc.repoStates[strings.ToLower(key.host+"/"+key.owner+"/"+key.repo)] = state
+			}
+		}
+	}
+	slog.Debug("github: prefetched via GraphQL", slog.Int("objects", len(proc.resolved)))
+}
+
+// resolve checks the existence of the objects in a single GraphQL query. Objects are grouped by repository,
+// the states of the repositories are returned by their 'repo' keys. The keys keep their hosts, because a client serves
+// several spellings of its host, e.g. 'github.com' and 'www.github.com':
+//
+//	r0: repository(owner: "org", name: "repo") { nameWithOwner isArchived o0: issueOrPullRequest(number: 1) { __typename } }
+func (w *wrapper) resolve(ctx context.Context, keys []graphQLKey) (map[graphQLKey]bool, map[graphQLKey]repoState, error) {
+	type repoKey struct{ host, owner, repo string }
+	var repos []repoKey
+	children := make(map[repoKey][]graphQLKey)
+	for _, key := range keys {
+		rk := repoKey{key.host, key.owner, key.repo}
+		if _, ok := children[rk]; !ok {
+			repos = append(repos, rk)
+			children[rk] = nil
+		}
+		if key.kind != "repo" {
+			children[rk] = append(children[rk], key)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("query {")
+	for i, rk := range repos {
+		fmt.Fprintf(&sb, " r%d: repository(owner: %s, name: %s) { nameWithOwner isArchived", i, quote(rk.owner), quote(rk.repo))
+		for j, key := range children[rk] {
+			fmt.Fprintf(&sb, " o%d: %s { __typename }", j, graphQLField(key))
+		}
+		sb.WriteString(" }")
+	}
+	sb.WriteString(" }")
+
+	var result struct {
+		Data   map[string]map[string]json.RawMessage `json:"data"`
+		Errors []struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	req, err := w.client.NewRequest(ctx, "POST", w.graphQLURL(), map[string]string{"query": sb.String()})
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := w.client.Do(req, &result); err != nil {
+		return nil, nil, err
+	}
+	for _, e := range result.Errors {
+		// missing objects are returned as null with NOT_FOUND errors
+		if e.Type != "NOT_FOUND" {
+			return nil, nil, fmt.Errorf("GraphQL error: %s", e.Message)
+		}
+	}
+
+	resolved := make(map[graphQLKey]bool, len(keys))
+	states := make(map[graphQLKey]repoState, len(repos))
+	for i, rk := range repos {
+		repoFields := result.Data[fmt.Sprintf("r%d", i)]
+		exists := repoFields != nil
+		repoKey := graphQLKey{host: rk.host, owner: rk.owner, repo: rk.repo, kind: "repo"}
+		resolved[repoKey] = exists
+		state := repoState{}
+		if exists {
+			_ = json.Unmarshal(repoFields["nameWithOwner"], &state.fullName)
+			_ = json.Unmarshal(repoFields["isArchived"], &state.archived)
+		}
+		states[repoKey] = state
+		for j, key := range children[rk] {
+			field, ok := repoFields[fmt.Sprintf("o%d", j)]
+			resolved[key] = exists && ok && string(field) != "null"
+		}
+	}
+	return resolved, states, nil
+}
+
+// graphQL runs the query with the variables and returns its data. A missing object is reported as not found,
+// GitHub returns it as null with a NOT_FOUND error.
+func (w *wrapper) graphQL(ctx context.Context, query string, variables map[string]any) (json.RawMessage, error) {
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	req, err := w.client.NewRequest(ctx, "POST", w.graphQLURL(), map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.client.Do(req, &result); err != nil {
+		return nil, err
+	}
+	for _, e := range result.Errors {
+		if e.Type == "NOT_FOUND" {
+			return nil, errs.NewNotFoundMessage(e.Message)
+		}
+		return nil, fmt.Errorf("GraphQL error: %s", e.Message)
+	}
+	return result.Data, nil
+}
+
+// graphQLField returns the repository field, which resolves the object
+func graphQLField(key graphQLKey) string {
+	switch key.kind {
+	case "issue":
+		return fmt.Sprintf("issueOrPullRequest(number: %s)", key.id)
+	case "pull":
+		return fmt.Sprintf("pullRequest(number: %s)", key.id)
+	case "release":
+		return fmt.Sprintf("release(tagName: %s)", quote(key.id))
+	case "discussion":
+		return fmt.Sprintf("discussion(number: %s)", key.id)
+	}
+	return ""
+}
+
+// quote returns the GraphQL string literal, its escaping is compatible with JSON
+func quote(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// graphQLURL returns the GraphQL endpoint derived from the REST API base url.
+// It is 'https://api.github.com/graphql' for github.com and 'https://[host]/api/graphql' for GitHub Enterprise.
+func (w *wrapper) graphQLURL() string {
+	return strings.TrimSuffix(w.client.BaseURL(), "v3/") + "graphql"
+}
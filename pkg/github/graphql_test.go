@@ -0,0 +1,218 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"link-validator/pkg/errs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v88/github"
+)
+
+func Test_graphQLKeyOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		want   graphQLKey
+		wantOk bool
+	}{
+		{
+			name:   "repository",
+			url:    "https://github.com/your-ko/link-validator",
+			want:   graphQLKey{host: "github.com", owner: "your-ko", repo: "link-validator", kind: "repo"},
+			wantOk: true,
+		},
+		{
+			name:   "repository pulls list",
+			url:    "https://github.com/your-ko/link-validator/pulls",
+			want:   graphQLKey{host: "github.com", owner: "your-ko", repo: "link-validator", kind: "repo"},
+			wantOk: true,
+		},
+		{
+			name:   "issue",
+			url:    "https://github.com/your-ko/link-validator/issues/42",
+			want:   graphQLKey{host: "github.com", owner: "your-ko", repo: "link-validator", kind: "issue", id: "42"},
+			wantOk: true,
+		},
+		{
+			name:   "pull request",
+			url:    "https://github.mycorp.com/your-ko/link-validator/pull/7",
+			want:   graphQLKey{host: "github.mycorp.com", owner: "your-ko", repo: "link-validator", kind: "pull", id: "7"},
+			wantOk: true,
+		},
+		{
+			name:   "release",
+			url:    "https://github.com/your-ko/link-validator/releases/tag/v1.2.3",
+			want:   graphQLKey{host: "github.com", owner: "your-ko", repo: "link-validator", kind: "release", id: "v1.2.3"},
+			wantOk: true,
+		},
+		{
+			name:   "discussion",
+			url:    "https://github.com/your-ko/link-validator/discussions/3",
+			want:   graphQLKey{host: "github.com", owner: "your-ko", repo: "link-validator", kind: "discussion", id: "3"},
+			wantOk: true,
+		},
+		{name: "issue comment requires REST", url: "https://github.com/your-ko/link-validator/issues/42#issuecomment-1"},
+		{name: "PR files require REST", url: "https://github.com/your-ko/link-validator/pull/7/files"},
+		{name: "blob requires REST", url: "https://github.com/your-ko/link-validator/blob/main/README.md"},
+		{name: "user", url: "https://github.com/your-ko"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gh, err := parseUrl(tt.url)
+			if err != nil {
+				t.Fatalf("parseUrl() error = %v", err)
+			}
+			got, ok := graphQLKeyOf(gh)
+			if ok != tt.wantOk {
+				t.Fatalf("graphQLKeyOf() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if ok && got != tt.want {
+				t.Errorf("graphQLKeyOf() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkProcessor_Prefetch(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/graphql" {
+			// everything must be resolved by GraphQL
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var body struct {
+			Query string `json:"query"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		queries = append(queries, body.Query)
+		_, _ = w.Write([]byte(`{
+			"data": {
+				"r0": {"nameWithOwner": "your-ko/link-validator", "isArchived": false, "o0": {"__typename": "Issue"}, "o1": null, "o2": {"__typename": "Release"}},
+				"r1": null,
+				"r2": {"nameWithOwner": "your-ko/link-validator", "isArchived": true, "o0": {"__typename": "Issue"}}
+			},
+			"errors": [{"type": "NOT_FOUND", "message": "Could not resolve to a PullRequest with the number of 2."}]
+		}`))
+	}))
+	defer server.Close()
+
+	c, err := github.NewClient(github.WithEnterpriseURLs(server.URL, server.URL))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	host := strings.TrimPrefix(server.URL, "http://")
+	client := &wrapper{client: c, limiter: newRateLimiter(host, time.Minute)}
+	proc := &LinkProcessor{
+		hosts:      map[string]*wrapper{strings.Split(host, ":")[0]: client},
+		client:     client,
+		graphQL:    true,
+		resolved:   make(map[graphQLKey]bool),
+		repoStates: make(map[string]repoState),
+	}
+
+	base := "https://" + host
+	links := []string{
+		base + "/your-ko/link-validator/issues/1",
+		base + "/your-ko/link-validator/pull/2",
+		base + "/your-ko/link-validator/releases/tag/v1.0.0",
+		base + "/your-ko/link-validator/issues/1",
+		base + "/your-ko/missing",
+		base + "/your-ko/old-name/issues/3",
+	}
+	proc.Prefetch(context.Background(), links)
+
+	if len(queries) != 1 {
+		t.Fatalf("expected a single GraphQL query, got %d", len(queries))
+	}
+	wantQuery := `query { r0: repository(owner: "your-ko", name: "link-validator") { nameWithOwner isArchived o0: issueOrPullRequest(number: 1) { __typename } o1: pullRequest(number: 2) { __typename } o2: release(tagName: "v1.0.0") { __typename } } r1: repository(owner: "your-ko", name: "missing") { nameWithOwner isArchived } r2: repository(owner: "your-ko", name: "old-name") { nameWithOwner isArchived o0: issueOrPullRequest(number: 3) { __typename } } }`
+	if queries[0] != wantQuery {
+		t.Errorf("query mismatch\ngot  = %s\nwant = %s", queries[0], wantQuery)
+	}
+
+	tests := []struct {
+		link   string
+		wantIs error
+	}{
+		{link: links[0]},
+		{link: links[1], wantIs: errs.ErrNotFound},
+		{link: links[2]},
+		{link: links[4], wantIs: errs.ErrNotFound},
+		// the repository state is checked without REST calls, the server fails them
+		{link: links[5], wantIs: errs.ErrRepoMoved},
+	}
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			err := proc.Process(context.Background(), tt.link, "README.md")
+			if tt.wantIs == nil && err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+			if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+				t.Errorf("expected \n errors.Is(err, %v) to be true; \n got err=%v", tt.wantIs, err)
+			}
+		})
+	}
+}
+
+func TestLinkProcessor_Prefetch_hostSpellings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/graphql" {
+			// everything must be resolved by GraphQL
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{
+			"data": {
+				"r0": {"nameWithOwner": "your-ko/link-validator", "isArchived": false, "o0": {"__typename": "Issue"}},
+				"r1": {"nameWithOwner": "your-ko/link-validator", "isArchived": false, "o0": {"__typename": "Issue"}}
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	c, err := github.NewClient(github.WithEnterpriseURLs(server.URL, server.URL))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	host := strings.TrimPrefix(server.URL, "http://")
+	port := strings.Split(host, ":")[1]
+	client := &wrapper{client: c, limiter: newRateLimiter(host, time.Minute)}
+	// the same client serves both spellings of the host
+	proc := &LinkProcessor{
+		hosts:      map[string]*wrapper{"127.0.0.1": client, "localhost": client},
+		client:     client,
+		graphQL:    true,
+		resolved:   make(map[graphQLKey]bool),
+		repoStates: make(map[string]repoState),
+	}
+
+	links := []string{
+		"https://127.0.0.1:" + port + "/your-ko/link-validator/issues/1",
+		"https://localhost:" + port + "/your-ko/link-validator/issues/2",
+	}
+	proc.Prefetch(context.Background(), links)
+
+	for _, link := range links {
+		gh, err := parseUrl(link)
+		if err != nil {
+			t.Fatalf("parseUrl() error = %v", err)
+		}
+		key, _ := graphQLKeyOf(gh)
+		if !proc.resolved[key] {
+			t.Errorf("%s is not resolved by GraphQL", link)
+		}
+		if _, ok := proc.repoStates[strings.ToLower(gh.host+"/"+gh.owner+"/"+gh.repo)]; !ok {
+			t.Errorf("the repository state of %s is not stored", link)
+		}
+		// the server fails REST calls, the link is validated by the prefetched results only
+		if err := proc.Process(context.Background(), link, "README.md"); err != nil {
+			t.Errorf("Process() error = %v", err)
+		}
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"link-validator/pkg/errs"
 	lvHttp "link-validator/pkg/http"
+	"link-validator/pkg/markdown"
 	"link-validator/pkg/regex"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"reflect"
 	"runtime"
 	"strconv"
@@ -17,6 +19,9 @@ import (
 	"github.com/google/go-github/v88/github"
 )
 
+// perPage is the maximum page size of GitHub list endpoints, it minimises the number of requests while paginating
+const perPage = 100
+
 type Handler interface {
 	Handle(ctx context.Context, gitHubClient *wrapper, httpClient *http.Client, gh *ghURL) error
 }
@@ -100,10 +105,27 @@ func handleRepoExist(ctx context.Context, c client, owner, repo, _, _, _ string)
 	return err
 }
 
-// handleContents validates existence either the metadata and content of a single file or subdirectories of a directory
+// handleAPI validates GitHub REST API urls, e.g. 'https://api.github.com/repos/owner/repo/issues/1',
+// by requesting the path with the authenticated client.
+func handleAPI(ctx context.Context, c client, _, _, _, path, _ string) error {
+	_, err := c.get(ctx, path)
+	return err
+}
+
+// handleContents validates existence either the metadata and content of a single file or subdirectories of a directory.
+// If the fragment is a line anchor (#L10-L25), the file content is decoded and the line range is validated as well.
+// GitHub renders Markdown files in blob and tree views, so line anchors in links to them are valid only in the plain
+// view ('?plain=1'), raw and blame views, and heading anchors are valid only in the rendered view.
 //
 //meta:operation GET /repos/{owner}/{repo}/contents/{path}
-func handleContents(ctx context.Context, c client, owner, repo, ref, path, _ string) error {
+func handleContents(ctx context.Context, c client, _ *http.Client, gh *ghURL) error {
+	rendered := (gh.typ == "blob" || gh.typ == "tree") && gh.query.Get("plain") != "1"
+	return validateContents(ctx, c, gh.owner, gh.repo, gh.ref, gh.path, gh.anchor, rendered)
+}
+
+// validateContents validates the file or the directory and the anchor, see handleContents.
+// Rendered is true if Markdown files are shown rendered, i.e. with heading anchors instead of line anchors.
+func validateContents(ctx context.Context, c client, owner, repo, ref, path, fragment string, rendered bool) error {
 	if strings.HasPrefix(path, "heads/") {
 		// extract the branch name
 		parts := strings.SplitN(strings.TrimPrefix(path, "heads/"), "/", 2)
@@ -112,8 +134,64 @@ func handleContents(ctx context.Context, c client, owner, repo, ref, path, _ str
 			path = parts[1]
 		}
 	}
-	_, _, _, err := c.getContents(ctx, owner, repo, ref, path)
-	return err
+	file, _, _, err := c.getContents(ctx, owner, repo, ref, path)
+	if err != nil {
+		return err
+	}
+	if fragment == "" || file == nil {
+		// no anchor or the path is a directory
+		return nil
+	}
+	lines := regex.LineAnchor.FindStringSubmatch(fragment)
+	if lines == nil && !markdown.IsMarkdown(path) {
+		// anchors in other files can't be validated
+		return nil
+	}
+	if markdown.IsMarkdown(path) {
+		if lines != nil && rendered {
+			return errs.NewNotFoundMessage(fmt.Sprintf("line anchor '#%s' in '%s' requires '?plain=1', GitHub renders Markdown files", fragment, file.GetPath()))
+		}
+		if lines == nil && !rendered {
+			return errs.NewNotFoundMessage(fmt.Sprintf("heading anchor '#%s' is not available in the plain text view of '%s'", fragment, file.GetPath()))
+		}
+	}
+	if file.GetEncoding() == "none" {
+		// files larger than 1MB are returned without content
+		slog.Debug("github: file is too large to validate the anchor", slog.String("path", file.GetPath()))
+		return nil
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return fmt.Errorf("can't decode content of '%s': %w", file.GetPath(), err)
+	}
+	if lines != nil {
+		return validateLineRange(file.GetPath(), content, lines[1], lines[2])
+	}
+	if !markdown.HasAnchor(markdown.Anchors(content), fragment) {
+		return errs.NewNotFoundMessage(fmt.Sprintf("heading anchor '#%s' not found in '%s'", fragment, file.GetPath()))
+	}
+	return nil
+}
+
+// validateLineRange validates that the lines referenced by the line anchor exist in the file content
+func validateLineRange(path, content, first, last string) error {
+	total := strings.Count(content, "\n")
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		total++
+	}
+
+	anchor := "L" + first
+	from, _ := strconv.Atoi(first)
+	to := from
+	if last != "" {
+		anchor = fmt.Sprintf("L%s-L%s", first, last)
+		to, _ = strconv.Atoi(last)
+	}
+	// GitHub highlights reversed ranges as well
+	if from < 1 || to < 1 || max(from, to) > total {
+		return errs.NewNotFoundMessage(fmt.Sprintf("line anchor '#%s' is out of range, '%s' has %d lines", anchor, path, total))
+	}
+	return nil
 }
 
 // handleCommit validates existence of the specified commit.
@@ -126,8 +204,18 @@ func handleCommit(ctx context.Context, c client, owner, repo, ref, path, fragmen
 	if err != nil {
 		return err
 	}
-	_, _, err = c.getCommit(ctx, owner, repo, ref, nil)
-	return err
+	commit, resp, err := c.getCommit(ctx, owner, repo, ref, nil)
+	if err != nil || !strings.HasPrefix(fragment, "diff-") {
+		return err
+	}
+	// https://github.com/your-ko/link-validator/commit/a96366f66ffacd461de10a1dd561ab5a598e9167#diff-48192d841d01a270fcd26b6e06f1e886333860b7f1ce32ae5758338d3c6551f7L10
+	return checkDiffAnchor(fragment, func() ([]*github.CommitFile, error) {
+		if resp == nil || resp.NextPage == 0 {
+			// the files of the commit fit into the first page
+			return commit.Files, nil
+		}
+		return listCommitFiles(ctx, c, owner, repo, ref)
+	})
 }
 
 // handleCompareCommits validates existence of the specified commit.
@@ -167,11 +255,13 @@ func handleCompareCommits(ctx context.Context, c client, owner, repo, ref, path,
 }
 
 // handlePull validates existence of a single pull request.
+// The pull request is kept in the url, so the state policies are applied without requesting it again.
 //
 // GitHub API docs: https://docs.github.com/rest/pulls/pulls#get-a-pull-request
 //
 //meta:operation GET /repos/{owner}/{repo}/pulls/{pull_number}
-func handlePull(ctx context.Context, c client, owner, repo, ref, path, fragment string) error {
+func handlePull(ctx context.Context, c client, _ *http.Client, gh *ghURL) error {
+	owner, repo, ref, path, fragment := gh.owner, gh.repo, gh.ref, gh.path, gh.anchor
 	prNumber, err := strconv.Atoi(ref)
 	if err != nil {
 		return fmt.Errorf("invalid PR number '%q'", ref)
@@ -181,7 +271,7 @@ func handlePull(ctx context.Context, c client, owner, repo, ref, path, fragment
 	if err != nil {
 		return err
 	}
-	_, _, err = c.getPR(ctx, owner, repo, prNumber)
+	gh.pull, _, err = c.getPR(ctx, owner, repo, prNumber)
 	if err != nil {
 		return err
 	}
@@ -196,15 +286,26 @@ func handlePull(ctx context.Context, c client, owner, repo, ref, path, fragment
 			//https://github.com/your-ko/link-validator/pull/280/commits/9130a7d501f28318d2761756f18b993b626181fa
 			//https://github.com/your-ko/link-validator/pull/280/commits/9130a7d501f28318d2761756f18b993b626181fa#diff-72ad4ae8af5a8d5be342d002cedff28908ba09b42e17197ed14b62081e62141dR31
 			SHA := strings.Split(path, "/")[1]
-			commits, _, err := c.listCommits(ctx, owner, repo, prNumber, nil)
-			if err != nil {
-				return err
-			}
-			for _, commit := range commits {
-				if commit.GetSHA() == SHA {
-					// unfortunately there is no API to find diff by SHA, so I ignore that bit
-					return nil
+			opts := &github.ListOptions{PerPage: perPage}
+			for {
+				commits, resp, err := c.listCommits(ctx, owner, repo, prNumber, opts)
+				if err != nil {
+					return err
+				}
+				for _, commit := range commits {
+					if commit.GetSHA() == SHA {
+						if !strings.HasPrefix(fragment, "diff-") {
+							return nil
+						}
+						return checkDiffAnchor(fragment, func() ([]*github.CommitFile, error) {
+							return listCommitFiles(ctx, c, owner, repo, SHA)
+						})
+					}
 				}
+				if resp == nil || resp.NextPage == 0 {
+					break
+				}
+				opts.Page = resp.NextPage
 			}
 			return fmt.Errorf("commit '%s' not found in PR '%s'", SHA, ref)
 		}
@@ -228,10 +329,11 @@ func handlePull(ctx context.Context, c client, owner, repo, ref, path, fragment
 		_, _, err = c.getPRComment(ctx, owner, repo, commentId)
 		return err
 	} else if strings.HasPrefix(fragment, "diff-") {
-		// https://github.com/your-ko/link-validator/pull/280/commits/9130a7d501f28318d2761756f18b993b626181fa#diff-72ad4ae8af5a8d5be342d002cedff28908ba09b42e17197ed14b62081e62141dL31
 		// https://github.com/your-ko/link-validator/pull/280/files#diff-48192d841d01a270fcd26b6e06f1e886333860b7f1ce32ae5758338d3c6551f7R10
-		// unfortunately there is no API to find diff by SHA, so I mark the URL as correct
-		return nil
+		// the hash is sha256 of the file path, so the anchored file is looked up in the files of the PR
+		return checkDiffAnchor(fragment, func() ([]*github.CommitFile, error) {
+			return listPRFiles(ctx, c, owner, repo, prNumber)
+		})
 	}
 
 	return fmt.Errorf("unsupported PR fragment format: '%s'. Please report a bug", fragment)
@@ -278,16 +380,22 @@ func handleSecurityAdvisories(ctx context.Context, c client, owner, repo, ref, p
 		return err
 	}
 
-	// Since there's no direct GetRepositoryAdvisory method, I list all advisories
-	advisories, _, err := c.listRepositorySecurityAdvisories(ctx, owner, repo, nil)
-	if err != nil {
-		return err
-	}
-
-	for _, advisory := range advisories {
-		if advisory.GetGHSAID() == ref {
-			return nil // Found the advisory
+	// Since there's no direct GetRepositoryAdvisory method, I list all advisories. The endpoint uses cursor pagination
+	opts := &github.ListRepositorySecurityAdvisoriesOptions{ListCursorOptions: github.ListCursorOptions{PerPage: perPage}}
+	for {
+		advisories, resp, err := c.listRepositorySecurityAdvisories(ctx, owner, repo, opts)
+		if err != nil {
+			return err
+		}
+		for _, advisory := range advisories {
+			if advisory.GetGHSAID() == ref {
+				return nil // Found the advisory
+			}
 		}
+		if resp == nil || resp.After == "" {
+			break
+		}
+		opts.After = resp.After
 	}
 
 	return fmt.Errorf("security advisory %q not found", ref)
@@ -365,11 +473,13 @@ func handleUser(ctx context.Context, c client, owner, _, _, _, _ string) error {
 }
 
 // handleIssue validates existence of a single issue.
+// The issue is kept in the url, so the state policies are applied without requesting it again.
 //
 // GitHub API docs: https://docs.github.com/rest/issues/issues#get-an-issue
 //
 //meta:operation GET /repos/{owner}/{repo}/issues/{issue_number}
-func handleIssue(ctx context.Context, c client, owner, repo, ref, path, fragment string) error {
+func handleIssue(ctx context.Context, c client, _ *http.Client, gh *ghURL) error {
+	owner, repo, ref, path, fragment := gh.owner, gh.repo, gh.ref, gh.path, gh.anchor
 	err := handleRepoExist(ctx, c, owner, repo, ref, path, fragment)
 	if err != nil {
 		return err
@@ -383,15 +493,25 @@ func handleIssue(ctx context.Context, c client, owner, repo, ref, path, fragment
 		return fmt.Errorf("invalid issue number %q", ref)
 	}
 
-	_, _, err = c.getIssue(ctx, owner, repo, n)
+	gh.issue, _, err = c.getIssue(ctx, owner, repo, n)
 	return err
 }
 
 // handleReleases handles
 // /<owner>/<repo>/releases
-// /<owner>/<repo>/releases/tag/<tag>
+// /<owner>/<repo>/releases/new
 // /<owner>/<repo>/releases/latest
-// etc
+// /<owner>/<repo>/releases/latest/download/<asset>
+// /<owner>/<repo>/releases/tag/<tag>
+// /<owner>/<repo>/releases/edit/<tag>
+// /<owner>/<repo>/releases/expanded_assets/<tag>
+// /<owner>/<repo>/releases/download/<tag>/<asset>
+//
+// GitHub API docs: https://docs.github.com/rest/releases/releases#get-the-latest-release
+// GitHub API docs: https://docs.github.com/rest/releases/releases#get-a-release-by-tag-name
+//
+//meta:operation GET /repos/{owner}/{repo}/releases/latest
+//meta:operation GET /repos/{owner}/{repo}/releases/tags/{tag}
 func handleReleases(ctx context.Context, c client, owner, repo, ref, path, fragment string) error {
 	err := handleRepoExist(ctx, c, owner, repo, ref, path, fragment)
 	if err != nil {
@@ -401,10 +521,20 @@ func handleReleases(ctx context.Context, c client, owner, repo, ref, path, fragm
 	case path == "latest":
 		_, _, err := c.getLatestRelease(ctx, owner, repo)
 		return err
-	case path == "":
+	case path == "", ref == "" && path == "new":
 		// presumably if the repo exists then the releases list exists as well
-		return handleRepoExist(ctx, c, owner, repo, ref, path, fragment)
-	case ref == "tag":
+		return nil
+	case ref == "" && strings.HasPrefix(path, "latest/download/"):
+		asset := strings.TrimPrefix(path, "latest/download/")
+		if asset == "" || strings.Contains(asset, "/") {
+			return fmt.Errorf("incorrect download path '%s' in the release url", path)
+		}
+		r, _, err := c.getLatestRelease(ctx, owner, repo)
+		if err != nil {
+			return err
+		}
+		return findAsset(r, asset)
+	case ref == "tag", ref == "edit", ref == "expanded_assets":
 		_, _, err := c.getReleaseByTag(ctx, owner, repo, path)
 		return err
 	case ref == "download":
@@ -416,37 +546,39 @@ func handleReleases(ctx context.Context, c client, owner, repo, ref, path, fragm
 		if err != nil {
 			return err
 		}
-		for _, asset := range r.Assets {
-			if asset.GetName() == parts[1] {
-				// we found an asset in the release
-				return nil
-			}
-		}
-		return fmt.Errorf("asset '%s' wasn't found in the release assets", parts[1])
+		return findAsset(r, parts[1])
 	}
 	return fmt.Errorf("unexpected release path '%s' found. Please report a bug", path)
 }
 
-// handleLabel validates existence of a label.
+// findAsset checks that the release has the asset with the given name
+func findAsset(r *github.RepositoryRelease, name string) error {
+	for _, asset := range r.Assets {
+		if asset.GetName() == name {
+			// we found an asset in the release
+			return nil
+		}
+	}
+	return fmt.Errorf("asset '%s' wasn't found in the release assets", name)
+}
+
+// handleLabel validates existence of a label. The list of labels is validated by the existence of the repository.
 //
-// GitHub API docs: https://docs.github.com/rest/issues/labels#list-labels-for-a-repository
+// GitHub API docs: https://docs.github.com/rest/issues/labels#get-a-label
 //
-//meta:operation GET /repos/{owner}/{repo}/labels
+//meta:operation GET /repos/{owner}/{repo}/labels/{name}
 func handleLabel(ctx context.Context, c client, owner, repo, ref, path, fragment string) error {
 	err := handleRepoExist(ctx, c, owner, repo, ref, path, fragment)
-	if err != nil {
-		return err
-	}
-	labels, _, err := c.listLabels(ctx, owner, repo, nil)
-	if err != nil {
+	if err != nil || ref == "" {
 		return err
 	}
-	for _, l := range labels {
-		if *l.Name == ref {
-			return nil
-		}
+	// go-github puts the name into the path as is, so it is escaped once here, e.g. 'kind/bug' is 'kind%2Fbug'
+	_, _, err = c.getLabel(ctx, owner, repo, url.PathEscape(ref))
+	var gitHubErr *github.ErrorResponse
+	if errors.As(err, &gitHubErr) && gitHubErr.Response.StatusCode == http.StatusNotFound {
+		return errs.NewNotFoundMessage(fmt.Sprintf("label '%s' not found", ref))
 	}
-	return errs.NewNotFoundMessage(fmt.Sprintf("label '%s' not found", ref))
+	return err
 }
 
 // handlePackages validates existence of GitHub packages.
@@ -519,7 +651,7 @@ func handleOrgExist(ctx context.Context, c client, owner, _, _, _, _ string) err
 	return err
 }
 
-// Get a gist.
+// Get a gist. Raw file urls and '#file-<name>' anchors are checked against the files of the gist.
 //
 // GitHub API docs: https://docs.github.com/rest/gists/gists#get-a-gist
 // GitHub API docs: https://docs.github.com/rest/gists/gists#get-a-gist-revision
@@ -528,13 +660,8 @@ func handleOrgExist(ctx context.Context, c client, owner, _, _, _, _ string) err
 //meta:operation GET /gists/{gist_id}
 //meta:operation GET /gists/{gist_id}/{sha}
 //meta:operation GET /gists/{gist_id}/comments/{comment_id}
-func handleGist(ctx context.Context, c client, _, repo, ref, _, fragment string) error {
-	if ref != "" {
-		_, _, err := c.getGistRevision(ctx, repo, ref)
-		return err
-	}
-
-	if strings.HasPrefix(fragment, "gistcomment-") {
+func handleGist(ctx context.Context, c client, _, repo, ref, path, fragment string) error {
+	if ref == "" && strings.HasPrefix(fragment, "gistcomment-") {
 		commentID, err := strconv.ParseInt(strings.TrimPrefix(fragment, "gistcomment-"), 10, 64)
 		if err != nil {
 			return fmt.Errorf("invalid gist comment id: '%s'", fragment)
@@ -543,8 +670,45 @@ func handleGist(ctx context.Context, c client, _, repo, ref, _, fragment string)
 		return err
 	}
 
-	_, _, err := c.getGist(ctx, repo)
-	return err
+	var gist *github.Gist
+	var err error
+	if ref != "" {
+		gist, _, err = c.getGistRevision(ctx, repo, ref)
+	} else {
+		gist, _, err = c.getGist(ctx, repo)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case path != "":
+		// raw file url
+		if _, ok := gist.Files[github.GistFilename(path)]; !ok {
+			return errs.NewNotFoundMessage(fmt.Sprintf("file '%s' not found in the gist '%s'", path, repo))
+		}
+	case strings.HasPrefix(fragment, "file-"):
+		// #file-<name>, e.g. '#file-readme-md' for 'README.md'
+		for name := range gist.Files {
+			if "file-"+gistFileAnchor(string(name)) == strings.ToLower(fragment) {
+				return nil
+			}
+		}
+		return errs.NewNotFoundMessage(fmt.Sprintf("file anchor '#%s' not found in the gist '%s'", fragment, repo))
+	}
+	return nil
+}
+
+// gistFileAnchor converts the gist file name to its anchor the way GitHub does it:
+// the name is lower-cased, the characters other than letters, digits, '_' and '-' are replaced with '-'
+func gistFileAnchor(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		}
+		return '-'
+	}, strings.ToLower(name))
 }
 
 // handleEnvironments checks whether the env with the given ID exists
@@ -563,14 +727,21 @@ func handleEnvironments(ctx context.Context, c client, owner, repo, ref, path, f
 		return fmt.Errorf("invalid environment id: '%s'", ref)
 	}
 
-	envs, _, err := c.listEnvironments(ctx, owner, repo, nil)
-	if err != nil {
-		return err
-	}
-	for _, env := range envs.Environments {
-		if *env.ID == envID {
-			return nil
+	opts := &github.EnvironmentListOptions{ListOptions: github.ListOptions{PerPage: perPage}}
+	for {
+		envs, resp, err := c.listEnvironments(ctx, owner, repo, opts)
+		if err != nil {
+			return err
+		}
+		for _, env := range envs.Environments {
+			if env.GetID() == envID {
+				return nil
+			}
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
 		}
+		opts.Page = resp.NextPage
 	}
 	return errs.NewNotFoundMessage(fmt.Sprintf("environment with id:%s not found", ref))
 }
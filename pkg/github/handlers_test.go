@@ -2,12 +2,16 @@ package github
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"link-validator/pkg/errs"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-github/v88/github"
 	"github.com/stretchr/testify/mock"
@@ -157,6 +161,7 @@ func Test_handleContents(t *testing.T) {
 		name      string
 		setupMock func(*mockclient)
 		args      args
+		plain     bool
 		wantErr   error
 	}{
 		{
@@ -198,6 +203,134 @@ func Test_handleContents(t *testing.T) {
 				m.EXPECT().getContents(mock.Anything, "your-ko", "link-validator", "main", "README.md").Return(content, nil, resp, nil)
 			},
 		},
+		{
+			name: "line anchor within the file",
+			args: args{"your-ko", "link-validator", "main", "main.go", "L2"},
+			setupMock: func(m *mockclient) {
+				content := &github.RepositoryContent{
+					Path:    github.Ptr("main.go"),
+					Content: github.Ptr("package main\n\nfunc main() {}\n"),
+				}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				m.EXPECT().getContents(mock.Anything, "your-ko", "link-validator", "main", "main.go").Return(content, nil, resp, nil)
+			},
+		},
+		{
+			name:  "line range with columns within base64 encoded file",
+			args:  args{"your-ko", "link-validator", "main", "README.md", "L1C2-L3C5"},
+			plain: true,
+			setupMock: func(m *mockclient) {
+				content := &github.RepositoryContent{
+					Path:     github.Ptr("README.md"),
+					Encoding: github.Ptr("base64"),
+					Content:  github.Ptr(base64.StdEncoding.EncodeToString([]byte("# Title\n\nText"))),
+				}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				m.EXPECT().getContents(mock.Anything, "your-ko", "link-validator", "main", "README.md").Return(content, nil, resp, nil)
+			},
+		},
+		{
+			name: "line range out of the file",
+			args: args{"your-ko", "link-validator", "main", "main.go", "L2-L25"},
+			setupMock: func(m *mockclient) {
+				content := &github.RepositoryContent{
+					Path:    github.Ptr("main.go"),
+					Content: github.Ptr("package main\n\nfunc main() {}\n"),
+				}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				m.EXPECT().getContents(mock.Anything, "your-ko", "link-validator", "main", "main.go").Return(content, nil, resp, nil)
+			},
+			wantErr: errs.NewNotFoundMessage("line anchor '#L2-L25' is out of range, 'main.go' has 3 lines"),
+		},
+		{
+			name: "reversed line range out of the file",
+			args: args{"your-ko", "link-validator", "main", "main.go", "L25-L2"},
+			setupMock: func(m *mockclient) {
+				content := &github.RepositoryContent{
+					Path:    github.Ptr("main.go"),
+					Content: github.Ptr("package main\n\nfunc main() {}\n"),
+				}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				m.EXPECT().getContents(mock.Anything, "your-ko", "link-validator", "main", "main.go").Return(content, nil, resp, nil)
+			},
+			wantErr: errs.NewNotFoundMessage("line anchor '#L25-L2' is out of range, 'main.go' has 3 lines"),
+		},
+		{
+			name: "heading anchor in markdown file",
+			args: args{"your-ko", "link-validator", "main", "docs/setup.md", "user-content-prerequisites"},
+			setupMock: func(m *mockclient) {
+				content := &github.RepositoryContent{
+					Path:    github.Ptr("docs/setup.md"),
+					Content: github.Ptr("# Setup\n\n## Prerequisites\n"),
+				}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				m.EXPECT().getContents(mock.Anything, "your-ko", "link-validator", "main", "docs/setup.md").Return(content, nil, resp, nil)
+			},
+		},
+		{
+			name: "missing heading anchor in markdown file",
+			args: args{"your-ko", "link-validator", "main", "docs/setup.md", "installation"},
+			setupMock: func(m *mockclient) {
+				content := &github.RepositoryContent{
+					Path:    github.Ptr("docs/setup.md"),
+					Content: github.Ptr("# Setup\n\n## Prerequisites\n"),
+				}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				m.EXPECT().getContents(mock.Anything, "your-ko", "link-validator", "main", "docs/setup.md").Return(content, nil, resp, nil)
+			},
+			wantErr: errs.NewNotFoundMessage("heading anchor '#installation' not found in 'docs/setup.md'"),
+		},
+		{
+			name: "line anchor in rendered markdown file",
+			args: args{"your-ko", "link-validator", "main", "docs/setup.md", "L2"},
+			setupMock: func(m *mockclient) {
+				content := &github.RepositoryContent{
+					Path:    github.Ptr("docs/setup.md"),
+					Content: github.Ptr("# Setup\n\n## Prerequisites\n"),
+				}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				m.EXPECT().getContents(mock.Anything, "your-ko", "link-validator", "main", "docs/setup.md").Return(content, nil, resp, nil)
+			},
+			wantErr: errs.NewNotFoundMessage("line anchor '#L2' in 'docs/setup.md' requires '?plain=1', GitHub renders Markdown files"),
+		},
+		{
+			name: "heading anchor in plain markdown file",
+			args: args{"your-ko", "link-validator", "main", "docs/setup.md", "prerequisites"},
+			setupMock: func(m *mockclient) {
+				content := &github.RepositoryContent{
+					Path:    github.Ptr("docs/setup.md"),
+					Content: github.Ptr("# Setup\n\n## Prerequisites\n"),
+				}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				m.EXPECT().getContents(mock.Anything, "your-ko", "link-validator", "main", "docs/setup.md").Return(content, nil, resp, nil)
+			},
+			plain:   true,
+			wantErr: errs.NewNotFoundMessage("heading anchor '#prerequisites' is not available in the plain text view of 'docs/setup.md'"),
+		},
+		{
+			name: "anchor in non-markdown file is not validated",
+			args: args{"your-ko", "link-validator", "main", "index.html", "installation"},
+			setupMock: func(m *mockclient) {
+				content := &github.RepositoryContent{
+					Path:    github.Ptr("index.html"),
+					Content: github.Ptr("<html></html>"),
+				}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				m.EXPECT().getContents(mock.Anything, "your-ko", "link-validator", "main", "index.html").Return(content, nil, resp, nil)
+			},
+		},
+		{
+			name: "line anchor in a large file is not validated",
+			args: args{"your-ko", "link-validator", "main", "big.json", "L100000"},
+			setupMock: func(m *mockclient) {
+				content := &github.RepositoryContent{
+					Path:     github.Ptr("big.json"),
+					Encoding: github.Ptr("none"),
+				}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				m.EXPECT().getContents(mock.Anything, "your-ko", "link-validator", "main", "big.json").Return(content, nil, resp, nil)
+			},
+		},
 		{
 			name: "file not found - 404",
 			args: args{"your-ko", "link-validator", "main", "nonexistent.md", ""},
@@ -236,7 +369,7 @@ func Test_handleContents(t *testing.T) {
 			mockClient := newMockclient(t)
 			tt.setupMock(mockClient)
 
-			err := handleContents(context.Background(), mockClient, tt.args.owner, tt.args.repo, tt.args.ref, tt.args.path, tt.args.fragment)
+			err := validateContents(context.Background(), mockClient, tt.args.owner, tt.args.repo, tt.args.ref, tt.args.path, tt.args.fragment, !tt.plain)
 
 			if !mockClient.AssertExpectations(t) {
 				return
@@ -263,6 +396,71 @@ func Test_handleContents(t *testing.T) {
 	}
 }
 
+func Test_handleContents_markdownViews(t *testing.T) {
+	content := &github.RepositoryContent{
+		Path:    github.Ptr("docs/setup.md"),
+		Content: github.Ptr("# Setup\n\n## Prerequisites\n"),
+	}
+	tests := []struct {
+		name    string
+		url     string
+		wantErr string
+	}{
+		{
+			name: "heading anchor in rendered view",
+			url:  "https://github.com/your-ko/link-validator/blob/main/docs/setup.md#prerequisites",
+		},
+		{
+			name:    "line anchor in rendered view",
+			url:     "https://github.com/your-ko/link-validator/blob/main/docs/setup.md#L3",
+			wantErr: "line anchor '#L3' in 'docs/setup.md' requires '?plain=1', GitHub renders Markdown files",
+		},
+		{
+			name: "line anchor in plain view",
+			url:  "https://github.com/your-ko/link-validator/blob/main/docs/setup.md?plain=1#L3",
+		},
+		{
+			name:    "line anchor out of range in plain view",
+			url:     "https://github.com/your-ko/link-validator/blob/main/docs/setup.md?plain=1#L10",
+			wantErr: "line anchor '#L10' is out of range, 'docs/setup.md' has 3 lines",
+		},
+		{
+			name:    "heading anchor in plain view",
+			url:     "https://github.com/your-ko/link-validator/blob/main/docs/setup.md?plain=1#prerequisites",
+			wantErr: "heading anchor '#prerequisites' is not available in the plain text view of 'docs/setup.md'",
+		},
+		{
+			name: "line anchor in blame view",
+			url:  "https://github.com/your-ko/link-validator/blame/main/docs/setup.md#L3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := newMockclient(t)
+			resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+			mockClient.EXPECT().getContents(mock.Anything, "your-ko", "link-validator", "main", "docs/setup.md").Return(content, nil, resp, nil).Once()
+			gh, err := parseUrl(tt.url)
+			if err != nil {
+				t.Fatalf("parseUrl() error = %v", err)
+			}
+
+			err = handleContents(context.Background(), mockClient, nil, gh)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("handleContents() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, errs.ErrNotFound) {
+				t.Errorf("expected \n errors.Is(err, %v) to be true; \n got err=%v", errs.ErrNotFound, err)
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("handleContents() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func Test_handleCommit(t *testing.T) {
 	type args struct {
 		owner    string
@@ -553,6 +751,36 @@ func Test_handlePull(t *testing.T) {
 			setupMock: func(m *mockclient) {},
 			wantErr:   errors.New("invalid PR number '\"abc\"'"),
 		},
+		{
+			name: "PR commit found on the second page",
+			args: args{"your-ko", "link-validator", "1", "commits/9130a7d501f28318d2761756f18b993b626181fa", ""},
+			setupMock: func(m *mockclient) {
+				pr := &github.PullRequest{Title: github.Ptr("great PR")}
+				first := []*github.RepositoryCommit{{SHA: github.Ptr("0000000000000000000000000000000000000000")}}
+				second := []*github.RepositoryCommit{{SHA: github.Ptr("9130a7d501f28318d2761756f18b993b626181fa")}}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				firstResp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}, NextPage: 2}
+				repo := &github.Repository{Name: github.Ptr("link-validator")}
+				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil)
+				m.EXPECT().getPR(mock.Anything, "your-ko", "link-validator", 1).Return(pr, resp, nil)
+				m.EXPECT().listCommits(mock.Anything, "your-ko", "link-validator", 1, &github.ListOptions{PerPage: 100}).Return(first, firstResp, nil).Once()
+				m.EXPECT().listCommits(mock.Anything, "your-ko", "link-validator", 1, &github.ListOptions{PerPage: 100, Page: 2}).Return(second, resp, nil).Once()
+			},
+		},
+		{
+			name: "PR commit not found",
+			args: args{"your-ko", "link-validator", "1", "commits/9130a7d501f28318d2761756f18b993b626181fa", ""},
+			setupMock: func(m *mockclient) {
+				pr := &github.PullRequest{Title: github.Ptr("great PR")}
+				commits := []*github.RepositoryCommit{{SHA: github.Ptr("0000000000000000000000000000000000000000")}}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				repo := &github.Repository{Name: github.Ptr("link-validator")}
+				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil)
+				m.EXPECT().getPR(mock.Anything, "your-ko", "link-validator", 1).Return(pr, resp, nil)
+				m.EXPECT().listCommits(mock.Anything, "your-ko", "link-validator", 1, &github.ListOptions{PerPage: 100}).Return(commits, resp, nil)
+			},
+			wantErr: errors.New("commit '9130a7d501f28318d2761756f18b993b626181fa' not found in PR '1'"),
+		},
 		{
 			name: "PR with issue comment",
 			args: args{"your-ko", "link-validator", "1", "", "issuecomment-123456"},
@@ -666,6 +894,51 @@ func Test_handlePull(t *testing.T) {
 				m.EXPECT().getPR(mock.Anything, "your-ko", "link-validator", 1).Return(pr, resp, nil)
 			},
 		},
+		{
+			name: "PR files with diff anchor",
+			args: args{"your-ko", "link-validator", "1", "files", "diff-b335630551682c19a781afebcf4d07bf978fb1f8ac04c6bf87428ed5106870f5"},
+			setupMock: func(m *mockclient) {
+				pr := &github.PullRequest{Title: github.Ptr("great PR")}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				repo := &github.Repository{Name: github.Ptr("link-validator")}
+				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil)
+				m.EXPECT().getPR(mock.Anything, "your-ko", "link-validator", 1).Return(pr, resp, nil)
+				m.EXPECT().listPRFiles(mock.Anything, "your-ko", "link-validator", 1, &github.ListOptions{PerPage: perPage}).
+					Return([]*github.CommitFile{{Filename: github.Ptr("README.md")}}, resp, nil)
+			},
+		},
+		{
+			name: "PR files with diff anchor of a file outside the PR",
+			args: args{"your-ko", "link-validator", "1", "files", "diff-b335630551682c19a781afebcf4d07bf978fb1f8ac04c6bf87428ed5106870f5R10"},
+			setupMock: func(m *mockclient) {
+				pr := &github.PullRequest{Title: github.Ptr("great PR")}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				repo := &github.Repository{Name: github.Ptr("link-validator")}
+				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil)
+				m.EXPECT().getPR(mock.Anything, "your-ko", "link-validator", 1).Return(pr, resp, nil)
+				m.EXPECT().listPRFiles(mock.Anything, "your-ko", "link-validator", 1, &github.ListOptions{PerPage: perPage}).
+					Return([]*github.CommitFile{{Filename: github.Ptr("go.mod")}}, resp, nil)
+			},
+			wantErr: errors.New("diff anchor '#diff-b335630551682c19a781afebcf4d07bf978fb1f8ac04c6bf87428ed5106870f5R10' doesn't match any file of the diff"),
+		},
+		{
+			name: "PR commit with diff anchor",
+			args: args{"your-ko", "link-validator", "1", "commits/9130a7d501f28318d2761756f18b993b626181fa", "diff-b335630551682c19a781afebcf4d07bf978fb1f8ac04c6bf87428ed5106870f5L3"},
+			setupMock: func(m *mockclient) {
+				pr := &github.PullRequest{Title: github.Ptr("great PR")}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				repo := &github.Repository{Name: github.Ptr("link-validator")}
+				sha := "9130a7d501f28318d2761756f18b993b626181fa"
+				commit := &github.RepositoryCommit{
+					SHA:   github.Ptr(sha),
+					Files: []*github.CommitFile{{Filename: github.Ptr("README.md"), Patch: github.Ptr("@@ -1,3 +1,3 @@\n a\n-b\n+c\n d")}},
+				}
+				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil)
+				m.EXPECT().getPR(mock.Anything, "your-ko", "link-validator", 1).Return(pr, resp, nil)
+				m.EXPECT().listCommits(mock.Anything, "your-ko", "link-validator", 1, mock.Anything).Return([]*github.RepositoryCommit{commit}, resp, nil)
+				m.EXPECT().getCommit(mock.Anything, "your-ko", "link-validator", sha, &github.ListOptions{PerPage: perPage}).Return(commit, resp, nil)
+			},
+		},
 		{
 			name: "commits list - repository not found",
 			args: args{"your-ko", "nonexistent-repo", "1", "commits", ""},
@@ -685,7 +958,8 @@ func Test_handlePull(t *testing.T) {
 			mockClient := newMockclient(t)
 			tt.setupMock(mockClient)
 
-			err := handlePull(context.Background(), mockClient, tt.args.owner, tt.args.repo, tt.args.ref, tt.args.path, tt.args.fragment)
+			gh := &ghURL{owner: tt.args.owner, repo: tt.args.repo, ref: tt.args.ref, path: tt.args.path, anchor: tt.args.fragment}
+			err := handlePull(context.Background(), mockClient, nil, gh)
 			if !mockClient.AssertExpectations(t) {
 				return
 			}
@@ -833,7 +1107,21 @@ func Test_handleSecurityAdvisories(t *testing.T) {
 				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
 				repo := &github.Repository{Name: github.Ptr("link-validator")}
 				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil)
-				m.EXPECT().listRepositorySecurityAdvisories(mock.Anything, "your-ko", "link-validator", (*github.ListRepositorySecurityAdvisoriesOptions)(nil)).Return(sa, resp, nil)
+				m.EXPECT().listRepositorySecurityAdvisories(mock.Anything, "your-ko", "link-validator", &github.ListRepositorySecurityAdvisoriesOptions{ListCursorOptions: github.ListCursorOptions{PerPage: 100}}).Return(sa, resp, nil)
+			},
+		},
+		{
+			name: "advisory found on the second page",
+			args: args{"your-ko", "link-validator", "GHSA-1234-5678-9012", "", ""},
+			setupMock: func(m *mockclient) {
+				first := []*github.SecurityAdvisory{{GHSAID: github.Ptr("GHSA-0000-0000-0000")}}
+				second := []*github.SecurityAdvisory{{GHSAID: github.Ptr("GHSA-1234-5678-9012")}}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				firstResp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}, After: "cursor"}
+				repo := &github.Repository{Name: github.Ptr("link-validator")}
+				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil)
+				m.EXPECT().listRepositorySecurityAdvisories(mock.Anything, "your-ko", "link-validator", &github.ListRepositorySecurityAdvisoriesOptions{ListCursorOptions: github.ListCursorOptions{PerPage: 100}}).Return(first, firstResp, nil).Once()
+				m.EXPECT().listRepositorySecurityAdvisories(mock.Anything, "your-ko", "link-validator", &github.ListRepositorySecurityAdvisoriesOptions{ListCursorOptions: github.ListCursorOptions{PerPage: 100, After: "cursor"}}).Return(second, resp, nil).Once()
 			},
 		},
 		{
@@ -853,7 +1141,7 @@ func Test_handleSecurityAdvisories(t *testing.T) {
 				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
 				repo := &github.Repository{Name: github.Ptr("link-validator")}
 				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil)
-				m.EXPECT().listRepositorySecurityAdvisories(mock.Anything, "your-ko", "link-validator", (*github.ListRepositorySecurityAdvisoriesOptions)(nil)).Return(sa, resp, nil)
+				m.EXPECT().listRepositorySecurityAdvisories(mock.Anything, "your-ko", "link-validator", &github.ListRepositorySecurityAdvisoriesOptions{ListCursorOptions: github.ListCursorOptions{PerPage: 100}}).Return(sa, resp, nil)
 			},
 			wantErr: errors.New("security advisory \"GHSA-nonexistent-id\" not found"),
 		},
@@ -868,7 +1156,7 @@ func Test_handleSecurityAdvisories(t *testing.T) {
 				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
 				repo := &github.Repository{Name: github.Ptr("link-validator")}
 				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil)
-				m.EXPECT().listRepositorySecurityAdvisories(mock.Anything, "your-ko", "link-validator", (*github.ListRepositorySecurityAdvisoriesOptions)(nil)).Return(sa, resp, nil)
+				m.EXPECT().listRepositorySecurityAdvisories(mock.Anything, "your-ko", "link-validator", &github.ListRepositorySecurityAdvisoriesOptions{ListCursorOptions: github.ListCursorOptions{PerPage: 100}}).Return(sa, resp, nil)
 			},
 			wantErr: errors.New("security advisory \"GHSA-1234-5678-9012\" not found"),
 		},
@@ -1290,7 +1578,8 @@ func Test_handleIssue(t *testing.T) {
 			mockClient := newMockclient(t)
 			tt.setupMock(mockClient)
 
-			err := handleIssue(context.Background(), mockClient, tt.args.owner, tt.args.repo, tt.args.ref, tt.args.path, tt.args.fragment)
+			gh := &ghURL{owner: tt.args.owner, repo: tt.args.repo, ref: tt.args.ref, path: tt.args.path, anchor: tt.args.fragment}
+			err := handleIssue(context.Background(), mockClient, nil, gh)
 			if !mockClient.AssertExpectations(t) {
 				return
 			}
@@ -1426,6 +1715,65 @@ func Test_handleReleases(t *testing.T) {
 			},
 			wantErr: errors.New("asset 'nonexistent.zip' wasn't found in the release assets"),
 		},
+		{
+			name: "download asset of the latest release",
+			args: args{"your-ko", "link-validator", "", "latest/download/link-validator_linux_amd64.tar.gz", ""},
+			setupMock: func(m *mockclient) {
+				release := &github.RepositoryRelease{
+					Name:   github.Ptr("cool release"),
+					Assets: []*github.ReleaseAsset{{Name: github.Ptr("link-validator_linux_amd64.tar.gz")}},
+				}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				repo := &github.Repository{Name: github.Ptr("link-validator")}
+				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil)
+				m.EXPECT().getLatestRelease(mock.Anything, "your-ko", "link-validator").Return(release, resp, nil)
+			},
+		},
+		{
+			name: "download asset of the latest release - asset not found",
+			args: args{"your-ko", "link-validator", "", "latest/download/link-validator_windows_amd64.zip", ""},
+			setupMock: func(m *mockclient) {
+				release := &github.RepositoryRelease{
+					Name:   github.Ptr("cool release"),
+					Assets: []*github.ReleaseAsset{{Name: github.Ptr("link-validator_linux_amd64.tar.gz")}},
+				}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				repo := &github.Repository{Name: github.Ptr("link-validator")}
+				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil)
+				m.EXPECT().getLatestRelease(mock.Anything, "your-ko", "link-validator").Return(release, resp, nil)
+			},
+			wantErr: errors.New("asset 'link-validator_windows_amd64.zip' wasn't found in the release assets"),
+		},
+		{
+			name: "download asset of the latest release - incorrect path format",
+			args: args{"your-ko", "link-validator", "", "latest/download/", ""},
+			setupMock: func(m *mockclient) {
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				repo := &github.Repository{Name: github.Ptr("link-validator")}
+				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil)
+			},
+			wantErr: errors.New("incorrect download path 'latest/download/' in the release url"),
+		},
+		{
+			name: "edit release",
+			args: args{"your-ko", "link-validator", "edit", "v1.0.0", ""},
+			setupMock: func(m *mockclient) {
+				release := &github.RepositoryRelease{Name: github.Ptr("cool release")}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				repo := &github.Repository{Name: github.Ptr("link-validator")}
+				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil)
+				m.EXPECT().getReleaseByTag(mock.Anything, "your-ko", "link-validator", "v1.0.0").Return(release, resp, nil)
+			},
+		},
+		{
+			name: "new release - repository exists",
+			args: args{"your-ko", "link-validator", "", "new", ""},
+			setupMock: func(m *mockclient) {
+				repo := &github.Repository{Name: github.Ptr("link-validator")}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil)
+			},
+		},
 		{
 			name: "unexpected release path",
 			args: args{"your-ko", "link-validator", "unknown", "some-path", ""},
@@ -1512,39 +1860,61 @@ func Test_handleLabel(t *testing.T) {
 		wantErr   error
 	}{
 		{
-			name: "label found in the list",
+			name: "label found",
 			args: args{"your-ko", "link-validator", "enhancement", "", ""},
 			setupMock: func(m *mockclient) {
-				label := []*github.Label{{Name: github.Ptr("enhancement")}}
+				label := &github.Label{Name: github.Ptr("enhancement")}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				repo := &github.Repository{Name: github.Ptr("link-validator")}
+				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil)
+				m.EXPECT().getLabel(mock.Anything, "your-ko", "link-validator", "enhancement").Return(label, resp, nil)
+			},
+		},
+		{
+			name: "label with spaces is escaped",
+			args: args{"your-ko", "link-validator", "good first issue", "", ""},
+			setupMock: func(m *mockclient) {
+				label := &github.Label{Name: github.Ptr("good first issue")}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				repo := &github.Repository{Name: github.Ptr("link-validator")}
+				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil)
+				m.EXPECT().getLabel(mock.Anything, "your-ko", "link-validator", "good%20first%20issue").Return(label, resp, nil)
+			},
+		},
+		{
+			name: "label with a slash is escaped once",
+			args: args{"your-ko", "link-validator", "kind/bug", "", ""},
+			setupMock: func(m *mockclient) {
+				label := &github.Label{Name: github.Ptr("kind/bug")}
 				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
 				repo := &github.Repository{Name: github.Ptr("link-validator")}
 				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil)
-				m.EXPECT().listLabels(mock.Anything, "your-ko", "link-validator", (*github.ListOptions)(nil)).Return(label, resp, nil)
+				m.EXPECT().getLabel(mock.Anything, "your-ko", "link-validator", "kind%2Fbug").Return(label, resp, nil)
 			},
 		},
 		{
-			name: "label not found - empty labels list",
+			name: "label not found",
 			args: args{"your-ko", "link-validator", "nonexistent", "", ""},
 			setupMock: func(m *mockclient) {
-				var label []*github.Label
+				err := &github.ErrorResponse{
+					Response: &http.Response{StatusCode: http.StatusNotFound},
+					Message:  "Not found",
+				}
 				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
 				repo := &github.Repository{Name: github.Ptr("link-validator")}
 				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil)
-				m.EXPECT().listLabels(mock.Anything, "your-ko", "link-validator", (*github.ListOptions)(nil)).Return(label, resp, nil)
+				m.EXPECT().getLabel(mock.Anything, "your-ko", "link-validator", "nonexistent").Return(nil, &github.Response{Response: err.Response}, err)
 			},
 			wantErr: errors.New("label 'nonexistent' not found"),
 		},
 		{
-			name: "label not found - case sensitive mismatch",
-			args: args{"your-ko", "link-validator", "Bug", "", ""},
+			name: "labels list",
+			args: args{"your-ko", "link-validator", "", "", ""},
 			setupMock: func(m *mockclient) {
-				label := []*github.Label{{Name: github.Ptr("bug")}}
 				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
 				repo := &github.Repository{Name: github.Ptr("link-validator")}
 				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil)
-				m.EXPECT().listLabels(mock.Anything, "your-ko", "link-validator", (*github.ListOptions)(nil)).Return(label, resp, nil)
 			},
-			wantErr: errors.New("label 'Bug' not found"),
 		},
 		{
 			name: "labels list - repository not found",
@@ -1589,6 +1959,62 @@ func Test_handleLabel(t *testing.T) {
 	}
 }
 
+func TestLinkProcessor_Process_label(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/api/v3/repos/your-ko/link-validator":
+			_, _ = w.Write([]byte(`{"full_name": "your-ko/link-validator"}`))
+		case "/api/v3/repos/your-ko/link-validator/labels/good%20first%20issue":
+			_, _ = w.Write([]byte(`{"name": "good first issue"}`))
+		case "/api/v3/repos/your-ko/link-validator/labels/kind%2Fbug":
+			_, _ = w.Write([]byte(`{"name": "kind/bug"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+		}
+	}))
+	defer server.Close()
+
+	c, err := github.NewClient(github.WithEnterpriseURLs(server.URL, server.URL))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	host := strings.TrimPrefix(server.URL, "http://")
+	client := &wrapper{client: c, limiter: newRateLimiter(host, time.Minute)}
+	proc := &LinkProcessor{
+		hosts:      map[string]*wrapper{strings.Split(host, ":")[0]: client},
+		client:     client,
+		repoStates: make(map[string]repoState),
+		refs:       make(map[string]map[string]bool),
+	}
+
+	base := "https://" + host + "/your-ko/link-validator"
+	tests := []struct {
+		link    string
+		wantErr string
+	}{
+		{link: base + "/labels"},
+		{link: base + "/labels/good%20first%20issue"},
+		{link: base + "/labels/kind%2Fbug"},
+		{link: base + "/labels/kind%2Fmissing", wantErr: "label 'kind/missing' not found"},
+		{link: base + "/labels/missing", wantErr: "label 'missing' not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			err := proc.Process(context.Background(), tt.link, "README.md")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Process() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, errs.ErrNotFound) || err.Error() != tt.wantErr {
+				t.Errorf("Process() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func Test_handleOrgExist(t *testing.T) {
 	type args struct {
 		owner    string
@@ -1858,6 +2284,44 @@ func Test_handleGist(t *testing.T) {
 				m.EXPECT().getGistComment(mock.Anything, "gist123", int64(12345)).Return(comment, resp, nil)
 			},
 		},
+		{
+			name: "gist with file anchor",
+			args: args{"your-ko", "gist123", "", "", "file-hello-world-go"},
+			setupMock: func(m *mockclient) {
+				gist := &github.Gist{ID: github.Ptr("gist123"), Files: map[github.GistFilename]github.GistFile{"README.md": {}, "hello world.go": {}}}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				m.EXPECT().getGist(mock.Anything, "gist123").Return(gist, resp, nil)
+			},
+		},
+		{
+			name: "gist with missing file anchor",
+			args: args{"your-ko", "gist123", "", "", "file-main-go"},
+			setupMock: func(m *mockclient) {
+				gist := &github.Gist{ID: github.Ptr("gist123"), Files: map[github.GistFilename]github.GistFile{"README.md": {}, "hello world.go": {}}}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				m.EXPECT().getGist(mock.Anything, "gist123").Return(gist, resp, nil)
+			},
+			wantErr: errors.New("file anchor '#file-main-go' not found in the gist 'gist123'"),
+		},
+		{
+			name: "raw gist file of a revision",
+			args: args{"your-ko", "gist123", "12345", "README.md", ""},
+			setupMock: func(m *mockclient) {
+				gist := &github.Gist{ID: github.Ptr("gist123"), Files: map[github.GistFilename]github.GistFile{"README.md": {}, "hello world.go": {}}}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				m.EXPECT().getGistRevision(mock.Anything, "gist123", "12345").Return(gist, resp, nil)
+			},
+		},
+		{
+			name: "missing raw gist file",
+			args: args{"your-ko", "gist123", "", "main.go", ""},
+			setupMock: func(m *mockclient) {
+				gist := &github.Gist{ID: github.Ptr("gist123"), Files: map[github.GistFilename]github.GistFile{"README.md": {}, "hello world.go": {}}}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				m.EXPECT().getGist(mock.Anything, "gist123").Return(gist, resp, nil)
+			},
+			wantErr: errors.New("file 'main.go' not found in the gist 'gist123'"),
+		},
 		{
 			name: "gist not found - 404",
 			args: args{"your-ko", "nonexistent", "", "", ""},
@@ -1955,7 +2419,7 @@ func Test_handleEnvironments(t *testing.T) {
 				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
 				repo := &github.Repository{Name: github.Ptr("link-validator")}
 				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil)
-				m.EXPECT().listEnvironments(mock.Anything, "your-ko", "link-validator", (*github.EnvironmentListOptions)(nil)).Return(envs, resp, nil)
+				m.EXPECT().listEnvironments(mock.Anything, "your-ko", "link-validator", &github.EnvironmentListOptions{ListOptions: github.ListOptions{PerPage: 100}}).Return(envs, resp, nil)
 			},
 		},
 		{
@@ -1972,10 +2436,24 @@ func Test_handleEnvironments(t *testing.T) {
 				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}
 				repo := &github.Repository{Name: github.Ptr("link-validator")}
 				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil)
-				m.EXPECT().listEnvironments(mock.Anything, "your-ko", "link-validator", (*github.EnvironmentListOptions)(nil)).Return(envs, resp, nil)
+				m.EXPECT().listEnvironments(mock.Anything, "your-ko", "link-validator", &github.EnvironmentListOptions{ListOptions: github.ListOptions{PerPage: 100}}).Return(envs, resp, nil)
 			},
 			wantErr: errors.New("environment with id:09876 not found"),
 		},
+		{
+			name: "env exists on the second page",
+			args: args{"your-ko", "link-validator", "12345", "", ""},
+			setupMock: func(m *mockclient) {
+				first := &github.EnvResponse{Environments: []*github.Environment{{ID: github.Ptr(int64(1))}}}
+				second := &github.EnvResponse{Environments: []*github.Environment{{ID: github.Ptr(int64(12345))}}}
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				firstResp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}, NextPage: 2}
+				repo := &github.Repository{Name: github.Ptr("link-validator")}
+				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil)
+				m.EXPECT().listEnvironments(mock.Anything, "your-ko", "link-validator", &github.EnvironmentListOptions{ListOptions: github.ListOptions{PerPage: 100}}).Return(first, firstResp, nil).Once()
+				m.EXPECT().listEnvironments(mock.Anything, "your-ko", "link-validator", &github.EnvironmentListOptions{ListOptions: github.ListOptions{PerPage: 100, Page: 2}}).Return(second, resp, nil).Once()
+			},
+		},
 		{
 			name: "env id is malformed",
 			args: args{"your-ko", "link-validator", "qwerty", "", ""},
@@ -2375,3 +2853,61 @@ func Test_handleHttp(t *testing.T) {
 		})
 	}
 }
+
+func Test_handleAPI(t *testing.T) {
+	notFound := &github.ErrorResponse{
+		Response: &http.Response{StatusCode: http.StatusNotFound},
+		Message:  "Not Found",
+	}
+	tests := []struct {
+		name      string
+		path      string
+		setupMock func(*mockclient)
+		wantErr   error
+	}{
+		{
+			name: "existing resource",
+			path: "repos/your-ko/link-validator/issues/1",
+			setupMock: func(m *mockclient) {
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				m.EXPECT().get(mock.Anything, "repos/your-ko/link-validator/issues/1").Return(resp, nil)
+			},
+		},
+		{
+			name: "query is kept",
+			path: "repos/your-ko/link-validator/contents/README.md?ref=main",
+			setupMock: func(m *mockclient) {
+				resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+				m.EXPECT().get(mock.Anything, "repos/your-ko/link-validator/contents/README.md?ref=main").Return(resp, nil)
+			},
+		},
+		{
+			name: "missing resource",
+			path: "repos/your-ko/link-validator/issues/100500",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().get(mock.Anything, "repos/your-ko/link-validator/issues/100500").Return(&github.Response{Response: notFound.Response}, notFound)
+			},
+			wantErr: notFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := newMockclient(t)
+			tt.setupMock(mockClient)
+
+			err := handleAPI(context.Background(), mockClient, "", "", "", tt.path, "")
+			if !mockClient.AssertExpectations(t) {
+				return
+			}
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
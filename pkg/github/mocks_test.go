@@ -6,6 +6,7 @@ package github
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/google/go-github/v88/github"
 	mock "github.com/stretchr/testify/mock"
@@ -138,6 +139,162 @@ func (_c *mockclient_compareCommits_Call) RunAndReturn(run func(ctx context.Cont
 	return _c
 }
 
+// get provides a mock function for the type mockclient
+func (_mock *mockclient) get(ctx context.Context, path string) (*github.Response, error) {
+	ret := _mock.Called(ctx, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for get")
+	}
+
+	var r0 *github.Response
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*github.Response, error)); ok {
+		return returnFunc(ctx, path)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *github.Response); ok {
+		r0 = returnFunc(ctx, path)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*github.Response)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, path)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockclient_get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'get'
+type mockclient_get_Call struct {
+	*mock.Call
+}
+
+// get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - path string
+func (_e *mockclient_Expecter) get(ctx interface{}, path interface{}) *mockclient_get_Call {
+	return &mockclient_get_Call{Call: _e.mock.On("get", ctx, path)}
+}
+
+func (_c *mockclient_get_Call) Run(run func(ctx context.Context, path string)) *mockclient_get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *mockclient_get_Call) Return(response *github.Response, err error) *mockclient_get_Call {
+	_c.Call.Return(response, err)
+	return _c
+}
+
+func (_c *mockclient_get_Call) RunAndReturn(run func(ctx context.Context, path string) (*github.Response, error)) *mockclient_get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// getBranch provides a mock function for the type mockclient
+func (_mock *mockclient) getBranch(ctx context.Context, owner string, repo string, branch string) (*github.Branch, *github.Response, error) {
+	ret := _mock.Called(ctx, owner, repo, branch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for getBranch")
+	}
+
+	var r0 *github.Branch
+	var r1 *github.Response
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) (*github.Branch, *github.Response, error)); ok {
+		return returnFunc(ctx, owner, repo, branch)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) *github.Branch); ok {
+		r0 = returnFunc(ctx, owner, repo, branch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*github.Branch)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string) *github.Response); ok {
+		r1 = returnFunc(ctx, owner, repo, branch)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*github.Response)
+		}
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, string, string, string) error); ok {
+		r2 = returnFunc(ctx, owner, repo, branch)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// mockclient_getBranch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'getBranch'
+type mockclient_getBranch_Call struct {
+	*mock.Call
+}
+
+// getBranch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - branch string
+func (_e *mockclient_Expecter) getBranch(ctx interface{}, owner interface{}, repo interface{}, branch interface{}) *mockclient_getBranch_Call {
+	return &mockclient_getBranch_Call{Call: _e.mock.On("getBranch", ctx, owner, repo, branch)}
+}
+
+func (_c *mockclient_getBranch_Call) Run(run func(ctx context.Context, owner string, repo string, branch string)) *mockclient_getBranch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockclient_getBranch_Call) Return(branch *github.Branch, response *github.Response, err error) *mockclient_getBranch_Call {
+	_c.Call.Return(branch, response, err)
+	return _c
+}
+
+func (_c *mockclient_getBranch_Call) RunAndReturn(run func(ctx context.Context, owner string, repo string, branch string) (*github.Branch, *github.Response, error)) *mockclient_getBranch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // getCommit provides a mock function for the type mockclient
 func (_mock *mockclient) getCommit(ctx context.Context, owner string, repo string, sha string, opts *github.ListOptions) (*github.RepositoryCommit, *github.Response, error) {
 	ret := _mock.Called(ctx, owner, repo, sha, opts)
@@ -750,6 +907,94 @@ func (_c *mockclient_getIssueComment_Call) RunAndReturn(run func(ctx context.Con
 	return _c
 }
 
+// getLabel provides a mock function for the type mockclient
+func (_mock *mockclient) getLabel(ctx context.Context, owner string, repo string, name string) (*github.Label, *github.Response, error) {
+	ret := _mock.Called(ctx, owner, repo, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for getLabel")
+	}
+
+	var r0 *github.Label
+	var r1 *github.Response
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) (*github.Label, *github.Response, error)); ok {
+		return returnFunc(ctx, owner, repo, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) *github.Label); ok {
+		r0 = returnFunc(ctx, owner, repo, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*github.Label)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string) *github.Response); ok {
+		r1 = returnFunc(ctx, owner, repo, name)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*github.Response)
+		}
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, string, string, string) error); ok {
+		r2 = returnFunc(ctx, owner, repo, name)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// mockclient_getLabel_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'getLabel'
+type mockclient_getLabel_Call struct {
+	*mock.Call
+}
+
+// getLabel is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - name string
+func (_e *mockclient_Expecter) getLabel(ctx interface{}, owner interface{}, repo interface{}, name interface{}) *mockclient_getLabel_Call {
+	return &mockclient_getLabel_Call{Call: _e.mock.On("getLabel", ctx, owner, repo, name)}
+}
+
+func (_c *mockclient_getLabel_Call) Run(run func(ctx context.Context, owner string, repo string, name string)) *mockclient_getLabel_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockclient_getLabel_Call) Return(label *github.Label, response *github.Response, err error) *mockclient_getLabel_Call {
+	_c.Call.Return(label, response, err)
+	return _c
+}
+
+func (_c *mockclient_getLabel_Call) RunAndReturn(run func(ctx context.Context, owner string, repo string, name string) (*github.Label, *github.Response, error)) *mockclient_getLabel_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // getLatestRelease provides a mock function for the type mockclient
 func (_mock *mockclient) getLatestRelease(ctx context.Context, owner string, repo string) (*github.RepositoryRelease, *github.Response, error) {
 	ret := _mock.Called(ctx, owner, repo)
@@ -1940,6 +2185,80 @@ func (_c *mockclient_getWorkflowRunByID_Call) RunAndReturn(run func(ctx context.
 	return _c
 }
 
+// graphQL provides a mock function for the type mockclient
+func (_mock *mockclient) graphQL(ctx context.Context, query string, variables map[string]any) (json.RawMessage, error) {
+	ret := _mock.Called(ctx, query, variables)
+
+	if len(ret) == 0 {
+		panic("no return value specified for graphQL")
+	}
+
+	var r0 json.RawMessage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, map[string]any) (json.RawMessage, error)); ok {
+		return returnFunc(ctx, query, variables)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, map[string]any) json.RawMessage); ok {
+		r0 = returnFunc(ctx, query, variables)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(json.RawMessage)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, map[string]any) error); ok {
+		r1 = returnFunc(ctx, query, variables)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockclient_graphQL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'graphQL'
+type mockclient_graphQL_Call struct {
+	*mock.Call
+}
+
+// graphQL is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query string
+//   - variables map[string]any
+func (_e *mockclient_Expecter) graphQL(ctx interface{}, query interface{}, variables interface{}) *mockclient_graphQL_Call {
+	return &mockclient_graphQL_Call{Call: _e.mock.On("graphQL", ctx, query, variables)}
+}
+
+func (_c *mockclient_graphQL_Call) Run(run func(ctx context.Context, query string, variables map[string]any)) *mockclient_graphQL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 map[string]any
+		if args[2] != nil {
+			arg2 = args[2].(map[string]any)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *mockclient_graphQL_Call) Return(rawMessage json.RawMessage, err error) *mockclient_graphQL_Call {
+	_c.Call.Return(rawMessage, err)
+	return _c
+}
+
+func (_c *mockclient_graphQL_Call) RunAndReturn(run func(ctx context.Context, query string, variables map[string]any) (json.RawMessage, error)) *mockclient_graphQL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // listCommits provides a mock function for the type mockclient
 func (_mock *mockclient) listCommits(ctx context.Context, owner string, repo string, number int, opts *github.ListOptions) ([]*github.RepositoryCommit, *github.Response, error) {
 	ret := _mock.Called(ctx, owner, repo, number, opts)
@@ -2122,57 +2441,146 @@ func (_c *mockclient_listEnvironments_Call) RunAndReturn(run func(ctx context.Co
 	return _c
 }
 
-// listLabels provides a mock function for the type mockclient
-func (_mock *mockclient) listLabels(ctx context.Context, owner string, repo string, opts *github.ListOptions) ([]*github.Label, *github.Response, error) {
-	ret := _mock.Called(ctx, owner, repo, opts)
+// listMatchingRefs provides a mock function for the type mockclient
+func (_mock *mockclient) listMatchingRefs(ctx context.Context, owner string, repo string, ref string) ([]*github.Reference, *github.Response, error) {
+	ret := _mock.Called(ctx, owner, repo, ref)
 
 	if len(ret) == 0 {
-		panic("no return value specified for listLabels")
+		panic("no return value specified for listMatchingRefs")
 	}
 
-	var r0 []*github.Label
+	var r0 []*github.Reference
 	var r1 *github.Response
 	var r2 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, *github.ListOptions) ([]*github.Label, *github.Response, error)); ok {
-		return returnFunc(ctx, owner, repo, opts)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) ([]*github.Reference, *github.Response, error)); ok {
+		return returnFunc(ctx, owner, repo, ref)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, *github.ListOptions) []*github.Label); ok {
-		r0 = returnFunc(ctx, owner, repo, opts)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) []*github.Reference); ok {
+		r0 = returnFunc(ctx, owner, repo, ref)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*github.Label)
+			r0 = ret.Get(0).([]*github.Reference)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, *github.ListOptions) *github.Response); ok {
-		r1 = returnFunc(ctx, owner, repo, opts)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string) *github.Response); ok {
+		r1 = returnFunc(ctx, owner, repo, ref)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*github.Response)
 		}
 	}
-	if returnFunc, ok := ret.Get(2).(func(context.Context, string, string, *github.ListOptions) error); ok {
-		r2 = returnFunc(ctx, owner, repo, opts)
+	if returnFunc, ok := ret.Get(2).(func(context.Context, string, string, string) error); ok {
+		r2 = returnFunc(ctx, owner, repo, ref)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// mockclient_listMatchingRefs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'listMatchingRefs'
+type mockclient_listMatchingRefs_Call struct {
+	*mock.Call
+}
+
+// listMatchingRefs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - ref string
+func (_e *mockclient_Expecter) listMatchingRefs(ctx interface{}, owner interface{}, repo interface{}, ref interface{}) *mockclient_listMatchingRefs_Call {
+	return &mockclient_listMatchingRefs_Call{Call: _e.mock.On("listMatchingRefs", ctx, owner, repo, ref)}
+}
+
+func (_c *mockclient_listMatchingRefs_Call) Run(run func(ctx context.Context, owner string, repo string, ref string)) *mockclient_listMatchingRefs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *mockclient_listMatchingRefs_Call) Return(references []*github.Reference, response *github.Response, err error) *mockclient_listMatchingRefs_Call {
+	_c.Call.Return(references, response, err)
+	return _c
+}
+
+func (_c *mockclient_listMatchingRefs_Call) RunAndReturn(run func(ctx context.Context, owner string, repo string, ref string) ([]*github.Reference, *github.Response, error)) *mockclient_listMatchingRefs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// listPRFiles provides a mock function for the type mockclient
+func (_mock *mockclient) listPRFiles(ctx context.Context, owner string, repo string, number int, opts *github.ListOptions) ([]*github.CommitFile, *github.Response, error) {
+	ret := _mock.Called(ctx, owner, repo, number, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for listPRFiles")
+	}
+
+	var r0 []*github.CommitFile
+	var r1 *github.Response
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, int, *github.ListOptions) ([]*github.CommitFile, *github.Response, error)); ok {
+		return returnFunc(ctx, owner, repo, number, opts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, int, *github.ListOptions) []*github.CommitFile); ok {
+		r0 = returnFunc(ctx, owner, repo, number, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*github.CommitFile)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, int, *github.ListOptions) *github.Response); ok {
+		r1 = returnFunc(ctx, owner, repo, number, opts)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*github.Response)
+		}
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, string, string, int, *github.ListOptions) error); ok {
+		r2 = returnFunc(ctx, owner, repo, number, opts)
 	} else {
 		r2 = ret.Error(2)
 	}
 	return r0, r1, r2
 }
 
-// mockclient_listLabels_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'listLabels'
-type mockclient_listLabels_Call struct {
+// mockclient_listPRFiles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'listPRFiles'
+type mockclient_listPRFiles_Call struct {
 	*mock.Call
 }
 
-// listLabels is a helper method to define mock.On call
+// listPRFiles is a helper method to define mock.On call
 //   - ctx context.Context
 //   - owner string
 //   - repo string
+//   - number int
 //   - opts *github.ListOptions
-func (_e *mockclient_Expecter) listLabels(ctx interface{}, owner interface{}, repo interface{}, opts interface{}) *mockclient_listLabels_Call {
-	return &mockclient_listLabels_Call{Call: _e.mock.On("listLabels", ctx, owner, repo, opts)}
+func (_e *mockclient_Expecter) listPRFiles(ctx interface{}, owner interface{}, repo interface{}, number interface{}, opts interface{}) *mockclient_listPRFiles_Call {
+	return &mockclient_listPRFiles_Call{Call: _e.mock.On("listPRFiles", ctx, owner, repo, number, opts)}
 }
 
-func (_c *mockclient_listLabels_Call) Run(run func(ctx context.Context, owner string, repo string, opts *github.ListOptions)) *mockclient_listLabels_Call {
+func (_c *mockclient_listPRFiles_Call) Run(run func(ctx context.Context, owner string, repo string, number int, opts *github.ListOptions)) *mockclient_listPRFiles_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -2186,26 +2594,31 @@ func (_c *mockclient_listLabels_Call) Run(run func(ctx context.Context, owner st
 		if args[2] != nil {
 			arg2 = args[2].(string)
 		}
-		var arg3 *github.ListOptions
+		var arg3 int
 		if args[3] != nil {
-			arg3 = args[3].(*github.ListOptions)
+			arg3 = args[3].(int)
+		}
+		var arg4 *github.ListOptions
+		if args[4] != nil {
+			arg4 = args[4].(*github.ListOptions)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
 			arg3,
+			arg4,
 		)
 	})
 	return _c
 }
 
-func (_c *mockclient_listLabels_Call) Return(labels []*github.Label, response *github.Response, err error) *mockclient_listLabels_Call {
-	_c.Call.Return(labels, response, err)
+func (_c *mockclient_listPRFiles_Call) Return(commitFiles []*github.CommitFile, response *github.Response, err error) *mockclient_listPRFiles_Call {
+	_c.Call.Return(commitFiles, response, err)
 	return _c
 }
 
-func (_c *mockclient_listLabels_Call) RunAndReturn(run func(ctx context.Context, owner string, repo string, opts *github.ListOptions) ([]*github.Label, *github.Response, error)) *mockclient_listLabels_Call {
+func (_c *mockclient_listPRFiles_Call) RunAndReturn(run func(ctx context.Context, owner string, repo string, number int, opts *github.ListOptions) ([]*github.CommitFile, *github.Response, error)) *mockclient_listPRFiles_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,75 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"link-validator/pkg/errs"
+	"link-validator/pkg/regex"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/v88/github"
+)
+
+// commitSHA matches full and abbreviated commit SHAs
+var commitSHA = regexp.MustCompile(`^[0-9a-fA-F]{7,40}$`)
+
+// checkPermalink reports blob/tree/blame links pinned to a branch instead of a commit SHA or a tag.
+// SHAs are recognised without API calls, other refs are branches if the repository has a branch with this name.
+// In fix mode the error contains the permalink to the current commit of the branch.
+//
+// GitHub API docs: https://docs.github.com/rest/branches/branches#get-a-branch
+//
+//meta:operation GET /repos/{owner}/{repo}/branches/{branch}
+func (proc *LinkProcessor) checkPermalink(ctx context.Context, c client, gh *ghURL) error {
+	if !proc.permalinks.IsEnabled() {
+		return nil
+	}
+	switch gh.typ {
+	case "blob", "tree", "blame":
+	default:
+		return nil
+	}
+	if gh.ref == "" || commitSHA.MatchString(gh.ref) {
+		return nil
+	}
+	if proc.permalinks.IsLineAnchorsOnly() && !regex.LineAnchor.MatchString(gh.anchor) {
+		return nil
+	}
+
+	// the branch name is escaped by go-github, refs with slashes like 'feature/new-api' are passed as is
+	branch, _, err := c.getBranch(ctx, gh.owner, gh.repo, gh.ref)
+	var gitHubErr *github.ErrorResponse
+	if errors.As(err, &gitHubErr) && gitHubErr.Response.StatusCode == http.StatusNotFound {
+		// not a branch, hence a tag
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	permalink := ""
+	if proc.permalinks.IsFix() {
+		permalink = pinTo(gh.url, gh.ref, branch.GetCommit().GetSHA())
+	}
+	return errs.NewNotPermalink(gh.url, gh.ref, permalink)
+}
+
+// pinTo replaces the ref of the blob/tree/blame link with the commit SHA, the query and the anchor are kept.
+// The ref might span several path segments, e.g. 'feature/new-api'.
+func pinTo(link, ref, sha string) string {
+	u, err := url.Parse(link)
+	if err != nil || sha == "" {
+		return ""
+	}
+	// '/owner/repo/blob/ref/path'
+	parts := strings.Split(u.Path, "/")
+	end := 4 + strings.Count(ref, "/") + 1
+	if len(parts) < end {
+		return ""
+	}
+	u.Path = strings.Join(append(append(parts[:4:4], sha), parts[end:]...), "/")
+	u.RawPath = ""
+	return u.String()
+}
@@ -0,0 +1,167 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"link-validator/pkg/config"
+	"link-validator/pkg/errs"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v88/github"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLinkProcessor_checkPermalink(t *testing.T) {
+	sha := "0123456789abcdef0123456789abcdef01234567"
+	branch := &github.Branch{Name: github.Ptr("main"), Commit: &github.RepositoryCommit{SHA: github.Ptr(sha)}}
+	resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+	notFound := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}, Message: "Branch not found"}
+
+	tests := []struct {
+		name       string
+		permalinks config.PermalinksConfig
+		url        string
+		// ref is the ref with slashes, as resolved by refCandidates
+		ref       string
+		setupMock func(m *mockclient)
+		wantErr   string
+	}{
+		{
+			name: "policy is disabled",
+			url:  "https://github.com/your-ko/link-validator/blob/main/README.md#L42",
+		},
+		{
+			name:       "link pinned to a branch",
+			permalinks: config.PermalinksConfig{Enabled: github.Ptr(true)},
+			url:        "https://github.com/your-ko/link-validator/blob/main/README.md",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().getBranch(mock.Anything, "your-ko", "link-validator", "main").Return(branch, resp, nil)
+			},
+			wantErr: "pinned to a branch, not to a commit 'main'. Incorrect link: 'https://github.com/your-ko/link-validator/blob/main/README.md'",
+		},
+		{
+			name:       "fix proposes the permalink",
+			permalinks: config.PermalinksConfig{Enabled: github.Ptr(true), Fix: github.Ptr(true)},
+			url:        "https://github.com/your-ko/link-validator/blame/main/pkg/foo.go?plain=1#L42-L45",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().getBranch(mock.Anything, "your-ko", "link-validator", "main").Return(branch, resp, nil)
+			},
+			wantErr: "pinned to a branch, not to a commit 'main'. " +
+				"Incorrect link: 'https://github.com/your-ko/link-validator/blame/main/pkg/foo.go?plain=1#L42-L45', " +
+				"permalink: 'https://github.com/your-ko/link-validator/blame/" + sha + "/pkg/foo.go?plain=1#L42-L45'",
+		},
+		{
+			name:       "link pinned to a branch with a slash",
+			permalinks: config.PermalinksConfig{Enabled: github.Ptr(true), Fix: github.Ptr(true)},
+			url:        "https://github.com/your-ko/link-validator/blob/feature/new-api/docs/x.md",
+			ref:        "feature/new-api",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().getBranch(mock.Anything, "your-ko", "link-validator", "feature/new-api").Return(branch, resp, nil)
+			},
+			wantErr: "pinned to a branch, not to a commit 'feature/new-api'. " +
+				"Incorrect link: 'https://github.com/your-ko/link-validator/blob/feature/new-api/docs/x.md', " +
+				"permalink: 'https://github.com/your-ko/link-validator/blob/" + sha + "/docs/x.md'",
+		},
+		{
+			name:       "link pinned to a commit",
+			permalinks: config.PermalinksConfig{Enabled: github.Ptr(true)},
+			url:        "https://github.com/your-ko/link-validator/tree/0123456/pkg",
+		},
+		{
+			name:       "link pinned to a tag",
+			permalinks: config.PermalinksConfig{Enabled: github.Ptr(true)},
+			url:        "https://github.com/your-ko/link-validator/blob/v1.0.0/README.md",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().getBranch(mock.Anything, "your-ko", "link-validator", "v1.0.0").Return(nil, &github.Response{Response: notFound.Response}, notFound)
+			},
+		},
+		{
+			name:       "link without line anchor is skipped",
+			permalinks: config.PermalinksConfig{Enabled: github.Ptr(true), LineAnchorsOnly: github.Ptr(true)},
+			url:        "https://github.com/your-ko/link-validator/blob/main/README.md#usage",
+		},
+		{
+			name:       "link with line anchor",
+			permalinks: config.PermalinksConfig{Enabled: github.Ptr(true), LineAnchorsOnly: github.Ptr(true)},
+			url:        "https://github.com/your-ko/link-validator/blob/main/pkg/foo.go#L42",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().getBranch(mock.Anything, "your-ko", "link-validator", "main").Return(branch, resp, nil)
+			},
+			wantErr: "pinned to a branch, not to a commit 'main'. Incorrect link: 'https://github.com/your-ko/link-validator/blob/main/pkg/foo.go#L42'",
+		},
+		{
+			name:       "other link types are not affected",
+			permalinks: config.PermalinksConfig{Enabled: github.Ptr(true)},
+			url:        "https://github.com/your-ko/link-validator/issues/1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := newMockclient(t)
+			if tt.setupMock != nil {
+				tt.setupMock(mockClient)
+			}
+			gh, err := parseUrl(tt.url)
+			if err != nil {
+				t.Fatalf("parseUrl() error = %v", err)
+			}
+			if tt.ref != "" {
+				gh.path = strings.TrimPrefix(gh.ref+"/"+gh.path, tt.ref+"/")
+				gh.ref = tt.ref
+			}
+			proc := &LinkProcessor{permalinks: tt.permalinks}
+
+			err = proc.checkPermalink(context.Background(), mockClient, gh)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkPermalink() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, errs.ErrNotPermalink) {
+				t.Errorf("expected \n errors.Is(err, %v) to be true; \n got err=%v", errs.ErrNotPermalink, err)
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("checkPermalink() error = %s, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_pinTo(t *testing.T) {
+	sha := "0123456789abcdef0123456789abcdef01234567"
+	tests := []struct {
+		name string
+		link string
+		ref  string
+		want string
+	}{
+		{
+			name: "blob",
+			link: "https://github.com/your-ko/link-validator/blob/main/README.md#L1",
+			ref:  "main",
+			want: "https://github.com/your-ko/link-validator/blob/" + sha + "/README.md#L1",
+		},
+		{
+			name: "ref with slashes",
+			link: "https://github.com/your-ko/link-validator/blob/feature/new-api/docs/x.md",
+			ref:  "feature/new-api",
+			want: "https://github.com/your-ko/link-validator/blob/" + sha + "/docs/x.md",
+		},
+		{
+			name: "tree root",
+			link: "https://github.com/your-ko/link-validator/tree/feature/new-api",
+			ref:  "feature/new-api",
+			want: "https://github.com/your-ko/link-validator/tree/" + sha,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pinTo(tt.link, tt.ref, sha); got != tt.want {
+				t.Errorf("pinTo() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
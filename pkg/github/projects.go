@@ -0,0 +1,72 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"link-validator/pkg/errs"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// ownerProjectQuery resolves the project of an organization or a user, both implement ProjectV2Owner
+const ownerProjectQuery = `query($login: String!, $number: Int!) {
+  repositoryOwner(login: $login) { ... on ProjectV2Owner { projectV2(number: $number) { id } } }
+}`
+
+const repoProjectQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) { projectV2(number: $number) { id } }
+}`
+
+// handleProjects validates Projects v2 of organizations ('orgs/<org>/projects/<n>'), users ('users/<user>/projects/<n>')
+// and repositories ('<owner>/<repo>/projects/<n>') via GraphQL, projects aren't available via REST API.
+// Lists of projects are validated by the existence of the owner. GraphQL requires a token, without it the link is validated via HTTP.
+//
+// GitHub API docs: https://docs.github.com/issues/planning-and-tracking-with-projects/automating-your-project/using-the-api-to-manage-projects
+func handleProjects(ctx context.Context, c client, httpClient *http.Client, gh *ghURL) error {
+	err := validateProject(ctx, c, gh)
+	if isUnauthorized(err) {
+		slog.Debug("github: GraphQL requires a token, delegating to HTTP validator", slog.String("url", gh.url))
+		return handleHttp(ctx, c, httpClient, gh)
+	}
+	return err
+}
+
+func validateProject(ctx context.Context, c client, gh *ghURL) error {
+	number, err := strconv.Atoi(gh.ref)
+	if err != nil {
+		if gh.repo != "" {
+			return handleRepoExist(ctx, c, gh.owner, gh.repo, "", "", "")
+		}
+		return handleUser(ctx, c, gh.owner, "", "", "", "")
+	}
+
+	var found bool
+	if gh.repo != "" {
+		var data struct {
+			Repository *struct {
+				ProjectV2 *struct{} `json:"projectV2"`
+			} `json:"repository"`
+		}
+		err = queryGraphQL(ctx, c, repoProjectQuery, map[string]any{"owner": gh.owner, "name": gh.repo, "number": number}, &data)
+		found = data.Repository != nil && data.Repository.ProjectV2 != nil
+	} else {
+		var data struct {
+			RepositoryOwner *struct {
+				ProjectV2 *struct{} `json:"projectV2"`
+			} `json:"repositoryOwner"`
+		}
+		err = queryGraphQL(ctx, c, ownerProjectQuery, map[string]any{"login": gh.owner, "number": number}, &data)
+		if err == nil && data.RepositoryOwner == nil {
+			return errs.NewNotFoundMessage(fmt.Sprintf("user or org '%s' not found", gh.owner))
+		}
+		found = data.RepositoryOwner != nil && data.RepositoryOwner.ProjectV2 != nil
+	}
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errs.NewNotFoundMessage(fmt.Sprintf("project '%d' not found", number))
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"link-validator/pkg/errs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v88/github"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_handleProjects(t *testing.T) {
+	resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+	unauthorized := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusUnauthorized}, Message: "Requires authentication"}
+
+	tests := []struct {
+		name       string
+		url        string
+		httpStatus int
+		setupMock  func(m *mockclient)
+		wantIs     error
+		wantErr    string
+	}{
+		{
+			name: "org project",
+			url:  "https://github.com/orgs/your-ko/projects/1",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().graphQL(mock.Anything, ownerProjectQuery, map[string]any{"login": "your-ko", "number": 1}).
+					Return(json.RawMessage(`{"repositoryOwner":{"projectV2":{"id":"PVT_1"}}}`), nil).Once()
+			},
+		},
+		{
+			name: "view of a user project",
+			url:  "https://github.com/users/your-ko/projects/3/views/2",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().graphQL(mock.Anything, ownerProjectQuery, map[string]any{"login": "your-ko", "number": 3}).
+					Return(json.RawMessage(`{"repositoryOwner":{"projectV2":{"id":"PVT_3"}}}`), nil).Once()
+			},
+		},
+		{
+			name: "missing project",
+			url:  "https://github.com/orgs/your-ko/projects/404",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().graphQL(mock.Anything, ownerProjectQuery, mock.Anything).
+					Return(nil, errs.NewNotFoundMessage("Could not resolve to a ProjectV2 with the number 404.")).Once()
+			},
+			wantIs:  errs.ErrNotFound,
+			wantErr: "Could not resolve to a ProjectV2 with the number 404.",
+		},
+		{
+			name: "missing owner",
+			url:  "https://github.com/users/nonexistent-user/projects/1",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().graphQL(mock.Anything, ownerProjectQuery, mock.Anything).
+					Return(json.RawMessage(`{"repositoryOwner":null}`), nil).Once()
+			},
+			wantIs:  errs.ErrNotFound,
+			wantErr: "user or org 'nonexistent-user' not found",
+		},
+		{
+			name: "repository project",
+			url:  "https://github.com/your-ko/link-validator/projects/2",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().graphQL(mock.Anything, repoProjectQuery, map[string]any{"owner": "your-ko", "name": "link-validator", "number": 2}).
+					Return(json.RawMessage(`{"repository":{"projectV2":null}}`), nil).Once()
+			},
+			wantIs:  errs.ErrNotFound,
+			wantErr: "project '2' not found",
+		},
+		{
+			name: "repository projects",
+			url:  "https://github.com/your-ko/link-validator/projects",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(&github.Repository{}, resp, nil).Once()
+			},
+		},
+		{
+			name: "user projects",
+			url:  "https://github.com/users/your-ko/projects",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().getUser(mock.Anything, "your-ko").Return(&github.User{}, resp, nil).Once()
+			},
+		},
+		{
+			name:       "without a token the page is requested via HTTP",
+			url:        "https://github.com/users/your-ko/projects/1",
+			httpStatus: http.StatusNotFound,
+			setupMock: func(m *mockclient) {
+				m.EXPECT().graphQL(mock.Anything, ownerProjectQuery, mock.Anything).Return(nil, unauthorized).Once()
+				m.EXPECT().getUser(mock.Anything, "your-ko").Return(&github.User{}, resp, nil).Once()
+			},
+			wantIs: errs.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				if tt.httpStatus == 0 {
+					t.Errorf("unexpected HTTP request %s", req.URL)
+				}
+				res.WriteHeader(tt.httpStatus)
+				_, _ = res.Write([]byte("<html><body>page</body></html>"))
+			}))
+			defer testServer.Close()
+
+			mockClient := newMockclient(t)
+			if tt.setupMock != nil {
+				tt.setupMock(mockClient)
+			}
+			gh, err := parseUrl(tt.url)
+			if err != nil {
+				t.Fatalf("parseUrl() error = %v", err)
+			}
+			gh.url = testServer.URL
+
+			err = handleProjects(context.Background(), mockClient, testServer.Client(), gh)
+			if tt.wantIs == nil && tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("handleProjects() error = %v", err)
+				}
+				return
+			}
+			if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+				t.Errorf("expected \n errors.Is(err, %v) to be true; \n got err=%v", tt.wantIs, err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("handleProjects() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v88/github"
+)
+
+// secondaryRateLimitWait is used when GitHub doesn't say how long to wait after hitting the secondary rate limit.
+// GitHub recommends waiting at least one minute.
+const secondaryRateLimitWait = time.Minute
+
+// rateLimiter tracks the GitHub API quota of a client and pauses until the rate limit reset
+// while the wait budget allows it. Once the budget is spent, the remaining links are not requested at all.
+type rateLimiter struct {
+	host       string
+	budget     time.Duration
+	waited     time.Duration
+	exhausted  bool
+	unverified int
+	// rates contains the last known quota per GitHub API resource (core, search, graphql, ...)
+	rates map[string]github.Rate
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func newRateLimiter(host string, budget time.Duration) *rateLimiter {
+	return &rateLimiter{
+		host:   host,
+		budget: budget,
+		rates:  make(map[string]github.Rate),
+		sleep:  sleep,
+	}
+}
+
+// transport returns the http client, which records the quota from the response headers of every GitHub API call
+func (l *rateLimiter) transport(httpClient *http.Client) *http.Client {
+	c := *httpClient
+	c.Transport = &rateTransport{base: httpClient.Transport, limiter: l}
+	return &c
+}
+
+// retry decides what to do with the error returned by GitHub API.
+// Returns true if the request should be repeated: either after waiting for the reset, or to report the link as unverified
+// when the wait budget is spent.
+func (l *rateLimiter) retry(ctx context.Context, err error) bool {
+	wait, limited := rateLimitWait(err)
+	if !limited {
+		return false
+	}
+	if wait > l.budget {
+		slog.Warn("github: rate limit exceeded and the wait budget is spent, the remaining links won't be verified",
+			slog.String("host", l.host), slog.Duration("reset in", wait), slog.Duration("budget", l.budget))
+		l.exhausted = true
+		return true
+	}
+	slog.Info("github: rate limit exceeded, waiting for the reset", slog.String("host", l.host), slog.Duration("wait", wait))
+	if err := l.sleep(ctx, wait); err != nil {
+		return false
+	}
+	l.budget -= wait
+	l.waited += wait
+	return true
+}
+
+// report logs the quota summary
+func (l *rateLimiter) report() {
+	for resource, rate := range l.rates {
+		slog.Info("GitHub API quota",
+			slog.String("host", l.host),
+			slog.String("resource", resource),
+			slog.Int("limit", rate.Limit),
+			slog.Int("used", rate.Used),
+			slog.Int("remaining", rate.Remaining),
+			slog.Time("reset", rate.Reset.Time),
+		)
+	}
+	if l.waited > 0 {
+		slog.Info("GitHub rate limit waits", slog.String("host", l.host), slog.Duration("waited", l.waited))
+	}
+	if l.unverified > 0 {
+		slog.Warn("GitHub links unverified (rate limited)", slog.String("host", l.host), slog.Int("links", l.unverified))
+	}
+}
+
+// rateLimitWait returns the time to wait if the error is caused by the primary or secondary rate limit
+func rateLimitWait(err error) (time.Duration, bool) {
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) {
+		return max(time.Until(rateErr.Rate.Reset.Time), 0), true
+	}
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		if abuseErr.RetryAfter != nil {
+			return *abuseErr.RetryAfter, true
+		}
+		return secondaryRateLimitWait, true
+	}
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type rateTransport struct {
+	base    http.RoundTripper
+	limiter *rateLimiter
+}
+
+func (t *rateTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	if rate, ok := parseRate(resp.Header); ok {
+		resource := resp.Header.Get("X-RateLimit-Resource")
+		if resource == "" {
+			resource = "core"
+		}
+		t.limiter.rates[resource] = rate
+	}
+	return resp, nil
+}
+
+// parseRate parses the quota from the GitHub API response headers
+func parseRate(header http.Header) (github.Rate, bool) {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return github.Rate{}, false
+	}
+	rate := github.Rate{Limit: limit}
+	rate.Remaining, _ = strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	rate.Used, _ = strconv.Atoi(header.Get("X-RateLimit-Used"))
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rate.Reset = github.Timestamp{Time: time.Unix(reset, 0)}
+	}
+	return rate, true
+}
@@ -0,0 +1,129 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"link-validator/pkg/errs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v88/github"
+)
+
+func Test_rateLimiter_retry(t *testing.T) {
+	retryAfter := 30 * time.Second
+	tests := []struct {
+		name          string
+		budget        time.Duration
+		err           error
+		want          bool
+		wantExhausted bool
+		wantWaited    time.Duration
+	}{
+		{
+			name:   "no error",
+			budget: time.Minute,
+		},
+		{
+			name:   "not a rate limit error",
+			budget: time.Minute,
+			err:    errors.New("boom"),
+		},
+		{
+			name:       "secondary rate limit within the budget",
+			budget:     time.Minute,
+			err:        &github.AbuseRateLimitError{RetryAfter: &retryAfter},
+			want:       true,
+			wantWaited: retryAfter,
+		},
+		{
+			name:       "secondary rate limit without retry-after",
+			budget:     time.Minute,
+			err:        &github.AbuseRateLimitError{},
+			want:       true,
+			wantWaited: secondaryRateLimitWait,
+		},
+		{
+			name:       "primary rate limit already reset",
+			budget:     time.Minute,
+			err:        &github.RateLimitError{Rate: github.Rate{Reset: github.Timestamp{Time: time.Now().Add(-time.Minute)}}},
+			want:       true,
+			wantWaited: 0,
+		},
+		{
+			name:          "primary rate limit exceeds the budget",
+			budget:        time.Minute,
+			err:           &github.RateLimitError{Rate: github.Rate{Reset: github.Timestamp{Time: time.Now().Add(time.Hour)}}},
+			want:          true,
+			wantExhausted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newRateLimiter("github.com", tt.budget)
+			var slept time.Duration
+			l.sleep = func(_ context.Context, d time.Duration) error {
+				slept += d
+				return nil
+			}
+			if got := l.retry(context.Background(), tt.err); got != tt.want {
+				t.Errorf("retry() = %v, want %v", got, tt.want)
+			}
+			if l.exhausted != tt.wantExhausted {
+				t.Errorf("exhausted = %v, want %v", l.exhausted, tt.wantExhausted)
+			}
+			if slept != tt.wantWaited || l.waited != tt.wantWaited {
+				t.Errorf("waited = %v (slept %v), want %v", l.waited, slept, tt.wantWaited)
+			}
+			if l.budget != tt.budget-tt.wantWaited {
+				t.Errorf("budget = %v, want %v", l.budget, tt.budget-tt.wantWaited)
+			}
+		})
+	}
+}
+
+func Test_rateLimiter_transport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4990")
+		w.Header().Set("X-RateLimit-Used", "10")
+		w.Header().Set("X-RateLimit-Reset", "1700000000")
+		if r.URL.Path == "/search" {
+			w.Header().Set("X-RateLimit-Resource", "search")
+		}
+	}))
+	defer server.Close()
+
+	l := newRateLimiter("github.com", time.Minute)
+	c := l.transport(&http.Client{})
+	for _, path := range []string{"/repos", "/search"} {
+		resp, err := c.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	want := github.Rate{Limit: 5000, Remaining: 4990, Used: 10, Reset: github.Timestamp{Time: time.Unix(1700000000, 0)}}
+	for _, resource := range []string{"core", "search"} {
+		if got := l.rates[resource]; got != want {
+			t.Errorf("rates[%s] = %v, want %v", resource, got, want)
+		}
+	}
+}
+
+func TestLinkProcessor_Process_rateLimited(t *testing.T) {
+	l := newRateLimiter("github.com", 0)
+	l.exhausted = true
+	proc := &LinkProcessor{client: &wrapper{limiter: l}}
+
+	err := proc.Process(context.Background(), "https://github.com/your-ko/link-validator/blob/main/README.md", "README.md")
+	if !errors.Is(err, errs.ErrRateLimited) {
+		t.Errorf("expected \n errors.Is(err, %v) to be true; \n got err=%v", errs.ErrRateLimited, err)
+	}
+	if l.unverified != 1 {
+		t.Errorf("unverified = %d, want 1", l.unverified)
+	}
+}
@@ -0,0 +1,95 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"link-validator/pkg/errs"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v88/github"
+)
+
+// ambiguousRefs are the link types, whose ref can't be told apart from the path by the url alone
+var ambiguousRefs = map[string]bool{
+	"blob":    true,
+	"tree":    true,
+	"blame":   true,
+	"raw":     true,
+	"commits": true,
+}
+
+// refCandidates returns the possible splits of 'ref/path' into the ref and the path, from the shortest ref to the longest one.
+// Refs may contain slashes, e.g. '/blob/feature/new-api/docs/x.md' points to 'docs/x.md' in the branch 'feature/new-api'.
+// Like GitHub, the prefixes are matched against the branches and tags of the repository. The refs starting with
+// the same segment are requested once per repository. If no prefix matches, the link is returned as parsed.
+//
+// GitHub API docs: https://docs.github.com/rest/git/refs#list-matching-references
+//
+//meta:operation GET /repos/{owner}/{repo}/git/matching-refs/{ref}
+func (proc *LinkProcessor) refCandidates(ctx context.Context, c client, gh *ghURL) ([]*ghURL, error) {
+	if !ambiguousRefs[gh.typ] || gh.ref == "" || gh.path == "" || commitSHA.MatchString(gh.ref) {
+		return []*ghURL{gh}, nil
+	}
+
+	key := strings.ToLower(gh.host+"/"+gh.owner+"/"+gh.repo) + ":" + gh.ref
+	refs, ok := proc.refs[key]
+	if !ok {
+		refs = make(map[string]bool)
+		for _, prefix := range []string{"heads/", "tags/"} {
+			matching, _, err := c.listMatchingRefs(ctx, gh.owner, gh.repo, prefix+gh.ref)
+			var gitHubErr *github.ErrorResponse
+			if errors.As(err, &gitHubErr) && gitHubErr.Response.StatusCode == http.StatusNotFound {
+				// the repository doesn't exist, the handler reports it
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			for _, ref := range matching {
+				refs[strings.TrimPrefix(ref.GetRef(), "refs/"+prefix)] = true
+			}
+		}
+		proc.refs[key] = refs
+	}
+
+	var candidates []*ghURL
+	segments := append([]string{gh.ref}, strings.Split(gh.path, "/")...)
+	for i := 1; i <= len(segments); i++ {
+		ref := strings.Join(segments[:i], "/")
+		if !refs[ref] {
+			continue
+		}
+		candidate := *gh
+		candidate.ref, candidate.path = ref, strings.Join(segments[i:], "/")
+		candidates = append(candidates, &candidate)
+	}
+	if len(candidates) == 0 {
+		return []*ghURL{gh}, nil
+	}
+	return candidates, nil
+}
+
+// handle validates the link by the handler. Ambiguous refs are resolved by trying the candidates until the ref
+// and the path resolve, the candidate which resolved is returned for the further checks.
+func (proc *LinkProcessor) handle(ctx context.Context, c *wrapper, handler Handler, gh *ghURL) (*ghURL, error) {
+	candidates, err := proc.refCandidates(ctx, c, gh)
+	if err != nil {
+		return gh, err
+	}
+	for _, candidate := range candidates {
+		err = handler.Handle(ctx, c, proc.httpClient, candidate)
+		if !isNotFound(err) {
+			return candidate, err
+		}
+	}
+	return gh, err
+}
+
+func isNotFound(err error) bool {
+	var gitHubErr *github.ErrorResponse
+	if errors.As(err, &gitHubErr) && gitHubErr.Response != nil && gitHubErr.Response.StatusCode == http.StatusNotFound {
+		return true
+	}
+	return errors.Is(err, errs.ErrNotFound)
+}
@@ -0,0 +1,128 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v88/github"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLinkProcessor_refCandidates(t *testing.T) {
+	resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+	notFound := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}, Message: "Not Found"}
+	refs := func(names ...string) []*github.Reference {
+		var refs []*github.Reference
+		for _, name := range names {
+			refs = append(refs, &github.Reference{Ref: github.Ptr("refs/" + name)})
+		}
+		return refs
+	}
+
+	type split struct{ ref, path string }
+	tests := []struct {
+		name      string
+		url       string
+		setupMock func(m *mockclient)
+		want      []split
+	}{
+		{
+			name: "branch with a slash",
+			url:  "https://github.com/your-ko/link-validator/blob/feature/new-api/docs/x.md",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().listMatchingRefs(mock.Anything, "your-ko", "link-validator", "heads/feature").
+					Return(refs("heads/feature-x", "heads/feature/new-api", "heads/feature/old-api"), resp, nil).Once()
+				m.EXPECT().listMatchingRefs(mock.Anything, "your-ko", "link-validator", "tags/feature").
+					Return(nil, resp, nil).Once()
+			},
+			want: []split{{"feature/new-api", "docs/x.md"}},
+		},
+		{
+			name: "tag with slashes pointing to the root",
+			url:  "https://github.com/your-ko/link-validator/tree/release/v1/2",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().listMatchingRefs(mock.Anything, "your-ko", "link-validator", "heads/release").
+					Return(nil, resp, nil).Once()
+				m.EXPECT().listMatchingRefs(mock.Anything, "your-ko", "link-validator", "tags/release").
+					Return(refs("tags/release/v1/2"), resp, nil).Once()
+			},
+			want: []split{{"release/v1/2", ""}},
+		},
+		{
+			name: "branch and tag prefixes are tried from the shortest",
+			url:  "https://github.com/your-ko/link-validator/blob/docs/v2/README.md",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().listMatchingRefs(mock.Anything, "your-ko", "link-validator", "heads/docs").
+					Return(refs("heads/docs"), resp, nil).Once()
+				m.EXPECT().listMatchingRefs(mock.Anything, "your-ko", "link-validator", "tags/docs").
+					Return(refs("tags/docs/v2"), resp, nil).Once()
+			},
+			want: []split{{"docs", "v2/README.md"}, {"docs/v2", "README.md"}},
+		},
+		{
+			name: "unknown ref is kept as parsed",
+			url:  "https://github.com/your-ko/link-validator/blob/missing/README.md",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().listMatchingRefs(mock.Anything, "your-ko", "link-validator", "heads/missing").
+					Return(nil, resp, nil).Once()
+				m.EXPECT().listMatchingRefs(mock.Anything, "your-ko", "link-validator", "tags/missing").
+					Return(nil, resp, nil).Once()
+			},
+			want: []split{{"missing", "README.md"}},
+		},
+		{
+			name: "missing repository",
+			url:  "https://github.com/your-ko/missing/blob/main/README.md",
+			setupMock: func(m *mockclient) {
+				m.EXPECT().listMatchingRefs(mock.Anything, "your-ko", "missing", "heads/main").
+					Return(nil, &github.Response{Response: notFound.Response}, notFound).Once()
+			},
+			want: []split{{"main", "README.md"}},
+		},
+		{
+			name: "commit SHA",
+			url:  "https://github.com/your-ko/link-validator/blob/0123456789abcdef0123456789abcdef01234567/README.md",
+			want: []split{{"0123456789abcdef0123456789abcdef01234567", "README.md"}},
+		},
+		{
+			name: "ref without path",
+			url:  "https://github.com/your-ko/link-validator/tree/main",
+			want: []split{{"main", ""}},
+		},
+		{
+			name: "other link types",
+			url:  "https://github.com/your-ko/link-validator/issues/1",
+			want: []split{{"1", ""}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := newMockclient(t)
+			if tt.setupMock != nil {
+				tt.setupMock(mockClient)
+			}
+			gh, err := parseUrl(tt.url)
+			if err != nil {
+				t.Fatalf("parseUrl() error = %v", err)
+			}
+			proc := &LinkProcessor{refs: make(map[string]map[string]bool)}
+
+			// the second call is answered from the cache, the mock expects a single request per prefix
+			for range 2 {
+				candidates, err := proc.refCandidates(context.Background(), mockClient, gh)
+				if err != nil {
+					t.Fatalf("refCandidates() error = %v", err)
+				}
+				var got []split
+				for _, c := range candidates {
+					got = append(got, split{c.ref, c.path})
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Fatalf("refCandidates() mismatch\ngot = %#v\nwant= %#v", got, tt.want)
+				}
+			}
+		})
+	}
+}
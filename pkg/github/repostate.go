@@ -0,0 +1,60 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"link-validator/pkg/errs"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v88/github"
+)
+
+// checkRepository warns about links to renamed, transferred and archived repositories.
+// GitHub redirects such links, so they are valid, but they are outdated or probably stale.
+// The state is requested once per repository, the result is reused for the rest of the links.
+//
+// GitHub API docs: https://docs.github.com/rest/repos/repos#get-a-repository
+//
+//meta:operation GET /repos/{owner}/{repo}
+func (proc *LinkProcessor) checkRepository(ctx context.Context, c client, gh *ghURL) error {
+	if gh.owner == "" || gh.repo == "" {
+		return nil
+	}
+	switch gh.typ {
+	case "gist", "teams", "user", "orgs", "nope":
+		return nil
+	}
+
+	key := strings.ToLower(gh.host + "/" + gh.owner + "/" + gh.repo)
+	state, ok := proc.repoStates[key]
+	if !ok {
+		repo, _, err := c.getRepository(ctx, gh.owner, gh.repo)
+		var gitHubErr *github.ErrorResponse
+		if errors.As(err, &gitHubErr) && gitHubErr.Response.StatusCode == http.StatusNotFound {
+			repo, err = nil, nil
+		}
+		if err != nil {
+			return err
+		}
+		state = repoState{}
+		if repo != nil {
+			state = repoState{fullName: repo.GetFullName(), archived: repo.GetArchived()}
+		}
+		proc.repoStates[key] = state
+	}
+
+	switch {
+	case state.fullName != "" && !strings.EqualFold(state.fullName, gh.owner+"/"+gh.repo):
+		return errs.NewRepoMoved(gh.url, state.fullName)
+	case state.archived:
+		return errs.NewRepoArchived(gh.url, state.fullName)
+	}
+	return nil
+}
+
+// repoState is the part of the repository metadata, which makes the links to it outdated
+type repoState struct {
+	fullName string
+	archived bool
+}
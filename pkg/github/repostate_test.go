@@ -0,0 +1,108 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"link-validator/pkg/errs"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v88/github"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLinkProcessor_checkRepository(t *testing.T) {
+	resp := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+	tests := []struct {
+		name      string
+		url       string
+		setupMock func(m *mockclient)
+		wantIs    error
+		wantErr   string
+	}{
+		{
+			name: "up to date repository",
+			url:  "https://github.com/your-ko/link-validator/blob/main/README.md",
+			setupMock: func(m *mockclient) {
+				repo := &github.Repository{FullName: github.Ptr("your-ko/link-validator")}
+				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil).Once()
+			},
+		},
+		{
+			name: "the name differs in case only",
+			url:  "https://github.com/Your-Ko/Link-Validator",
+			setupMock: func(m *mockclient) {
+				repo := &github.Repository{FullName: github.Ptr("your-ko/link-validator")}
+				m.EXPECT().getRepository(mock.Anything, "Your-Ko", "Link-Validator").Return(repo, resp, nil).Once()
+			},
+		},
+		{
+			name: "renamed repository",
+			url:  "https://github.com/your-ko/old-name/issues/1",
+			setupMock: func(m *mockclient) {
+				repo := &github.Repository{FullName: github.Ptr("your-ko/link-validator")}
+				m.EXPECT().getRepository(mock.Anything, "your-ko", "old-name").Return(repo, resp, nil).Once()
+			},
+			wantIs:  errs.ErrRepoMoved,
+			wantErr: "repository has been renamed or transferred, the canonical name is 'your-ko/link-validator'. Outdated link: 'https://github.com/your-ko/old-name/issues/1'",
+		},
+		{
+			name: "transferred and archived repository",
+			url:  "https://github.com/old-owner/link-validator",
+			setupMock: func(m *mockclient) {
+				repo := &github.Repository{FullName: github.Ptr("your-ko/link-validator"), Archived: github.Ptr(true)}
+				m.EXPECT().getRepository(mock.Anything, "old-owner", "link-validator").Return(repo, resp, nil).Once()
+			},
+			wantIs:  errs.ErrRepoMoved,
+			wantErr: "repository has been renamed or transferred, the canonical name is 'your-ko/link-validator'. Outdated link: 'https://github.com/old-owner/link-validator'",
+		},
+		{
+			name: "archived repository",
+			url:  "https://github.com/your-ko/link-validator/pull/2",
+			setupMock: func(m *mockclient) {
+				repo := &github.Repository{FullName: github.Ptr("your-ko/link-validator"), Archived: github.Ptr(true)}
+				m.EXPECT().getRepository(mock.Anything, "your-ko", "link-validator").Return(repo, resp, nil).Once()
+			},
+			wantIs:  errs.ErrRepoArchived,
+			wantErr: "repository is archived, the canonical name is 'your-ko/link-validator'. Probably stale link: 'https://github.com/your-ko/link-validator/pull/2'",
+		},
+		{
+			name: "gists are not repositories",
+			url:  "https://gist.github.com/your-ko/0123456789abcdef",
+		},
+		{
+			name: "users are not repositories",
+			url:  "https://github.com/your-ko",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := newMockclient(t)
+			if tt.setupMock != nil {
+				tt.setupMock(mockClient)
+			}
+			gh, err := parseUrl(tt.url)
+			if err != nil {
+				t.Fatalf("parseUrl() error = %v", err)
+			}
+			proc := &LinkProcessor{repoStates: make(map[string]repoState)}
+
+			// the second call is answered from the cache, the mock expects a single request
+			for range 2 {
+				err = proc.checkRepository(context.Background(), mockClient, gh)
+				if tt.wantIs == nil {
+					if err != nil {
+						t.Fatalf("checkRepository() error = %v", err)
+					}
+					continue
+				}
+				if !errors.Is(err, tt.wantIs) {
+					t.Errorf("expected \n errors.Is(err, %v) to be true; \n got err=%v", tt.wantIs, err)
+				}
+				if err.Error() != tt.wantErr {
+					t.Errorf("checkRepository() error = %s, want %s", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package github
+
+import (
+	"link-validator/pkg/errs"
+	"slices"
+	"strings"
+)
+
+// checkState applies the state policies to the links to issues and pull requests.
+// The link is valid, but the issue is closed, locked or labeled, or the pull request is closed without merging.
+// Violations of 'fail' policies fail the validation, the rest are warnings.
+// The issue and the pull request are the ones handleIssue and handlePull requested, no API calls are made here.
+func (proc *LinkProcessor) checkState(gh *ghURL) error {
+	policies := proc.statePolicies
+
+	var states []string
+	fail := false
+	violate := func(policy, state string) {
+		states = append(states, state)
+		fail = fail || policy == "fail"
+	}
+	switch {
+	case gh.issue != nil && policies.HasIssuePolicies():
+		issue := gh.issue
+		if policies.ClosedIssue != "" && issue.GetState() == "closed" {
+			state := "issue is closed"
+			if reason := issue.GetStateReason(); reason != "" {
+				state += " as " + strings.ReplaceAll(reason, "_", " ")
+			}
+			violate(policies.ClosedIssue, state)
+		}
+		if policies.LockedIssue != "" && issue.GetLocked() {
+			violate(policies.LockedIssue, "issue is locked")
+		}
+		if policies.LabeledIssue != "" {
+			for _, label := range issue.Labels {
+				if slices.ContainsFunc(policies.IssueLabels, func(l string) bool { return strings.EqualFold(l, label.GetName()) }) {
+					violate(policies.LabeledIssue, "issue has label '"+label.GetName()+"'")
+				}
+			}
+		}
+	case gh.pull != nil && policies.UnmergedPull != "":
+		pr := gh.pull
+		if pr.GetState() == "closed" && !pr.GetMerged() {
+			violate(policies.UnmergedPull, "pull request is closed without merging")
+		}
+	}
+	if len(states) == 0 {
+		return nil
+	}
+	return errs.NewStatePolicy(gh.url, strings.Join(states, ", "), fail)
+}
@@ -0,0 +1,164 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"link-validator/pkg/config"
+	"link-validator/pkg/errs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v88/github"
+)
+
+func TestLinkProcessor_checkState(t *testing.T) {
+	tests := []struct {
+		name     string
+		policies config.StatePoliciesConfig
+		url      string
+		issue    *github.Issue
+		pull     *github.PullRequest
+		wantErr  string
+		wantFail bool
+	}{
+		{
+			name:  "policies are disabled",
+			url:   "https://github.com/your-ko/link-validator/issues/1",
+			issue: &github.Issue{State: github.Ptr("closed")},
+		},
+		{
+			name:     "open issue",
+			policies: config.StatePoliciesConfig{ClosedIssue: "fail", LockedIssue: "warn"},
+			url:      "https://github.com/your-ko/link-validator/issues/1",
+			issue:    &github.Issue{State: github.Ptr("open")},
+		},
+		{
+			name:     "issue closed as not planned",
+			policies: config.StatePoliciesConfig{ClosedIssue: "warn"},
+			url:      "https://github.com/your-ko/link-validator/issues/1#issuecomment-123",
+			issue:    &github.Issue{State: github.Ptr("closed"), StateReason: github.Ptr("not_planned")},
+			wantErr:  "violates the state policy: issue is closed as not planned. Link: 'https://github.com/your-ko/link-validator/issues/1#issuecomment-123'",
+		},
+		{
+			name: "locked issue with a label",
+			policies: config.StatePoliciesConfig{
+				LockedIssue:  "warn",
+				LabeledIssue: "fail",
+				IssueLabels:  []string{"wontfix", "invalid"},
+			},
+			url: "https://github.com/your-ko/link-validator/issues/2",
+			issue: &github.Issue{
+				State:  github.Ptr("open"),
+				Locked: github.Ptr(true),
+				Labels: []*github.Label{{Name: github.Ptr("bug")}, {Name: github.Ptr("WontFix")}},
+			},
+			wantErr:  "violates the state policy: issue is locked, issue has label 'WontFix'. Link: 'https://github.com/your-ko/link-validator/issues/2'",
+			wantFail: true,
+		},
+		{
+			name:     "list of issues",
+			policies: config.StatePoliciesConfig{ClosedIssue: "fail"},
+			url:      "https://github.com/your-ko/link-validator/issues",
+		},
+		{
+			name:     "merged pull request",
+			policies: config.StatePoliciesConfig{UnmergedPull: "fail"},
+			url:      "https://github.com/your-ko/link-validator/pull/3",
+			pull:     &github.PullRequest{State: github.Ptr("closed"), Merged: github.Ptr(true)},
+		},
+		{
+			name:     "pull request closed without merging",
+			policies: config.StatePoliciesConfig{UnmergedPull: "fail"},
+			url:      "https://github.com/your-ko/link-validator/pull/4/files",
+			pull:     &github.PullRequest{State: github.Ptr("closed"), Merged: github.Ptr(false)},
+			wantErr:  "violates the state policy: pull request is closed without merging. Link: 'https://github.com/your-ko/link-validator/pull/4/files'",
+			wantFail: true,
+		},
+		{
+			name:     "issue policies don't apply to pull requests",
+			policies: config.StatePoliciesConfig{ClosedIssue: "fail"},
+			url:      "https://github.com/your-ko/link-validator/pull/4",
+			pull:     &github.PullRequest{State: github.Ptr("closed"), Merged: github.Ptr(false)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gh, err := parseUrl(tt.url)
+			if err != nil {
+				t.Fatalf("parseUrl() error = %v", err)
+			}
+			gh.issue, gh.pull = tt.issue, tt.pull
+			proc := &LinkProcessor{statePolicies: tt.policies}
+
+			err = proc.checkState(gh)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkState() error = %v", err)
+				}
+				return
+			}
+			var stateErr errs.StatePolicyError
+			if !errors.As(err, &stateErr) {
+				t.Fatalf("expected \n errors.As(err, %T) to be true; \n got err=%v", stateErr, err)
+			}
+			if !errors.Is(err, errs.ErrStatePolicy) {
+				t.Errorf("expected \n errors.Is(err, %v) to be true; \n got err=%v", errs.ErrStatePolicy, err)
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("checkState() error = %s, want %s", err, tt.wantErr)
+			}
+			if stateErr.Fail != tt.wantFail {
+				t.Errorf("checkState() fail = %v, want %v", stateErr.Fail, tt.wantFail)
+			}
+		})
+	}
+}
+
+func TestLinkProcessor_Process_statePolicies(t *testing.T) {
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/api/v3/repos/your-ko/link-validator":
+			_, _ = w.Write([]byte(`{"full_name": "your-ko/link-validator"}`))
+		case "/api/v3/repos/your-ko/link-validator/issues/1":
+			_, _ = w.Write([]byte(`{"number": 1, "state": "closed", "state_reason": "completed"}`))
+		case "/api/v3/repos/your-ko/link-validator/pulls/2":
+			_, _ = w.Write([]byte(`{"number": 2, "state": "closed", "merged": false}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+		}
+	}))
+	defer server.Close()
+
+	c, err := github.NewClient(github.WithEnterpriseURLs(server.URL, server.URL))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	host := strings.TrimPrefix(server.URL, "http://")
+	client := &wrapper{client: c, limiter: newRateLimiter(host, time.Minute)}
+	proc := &LinkProcessor{
+		hosts:         map[string]*wrapper{strings.Split(host, ":")[0]: client},
+		client:        client,
+		statePolicies: config.StatePoliciesConfig{ClosedIssue: "warn", UnmergedPull: "fail"},
+		repoStates:    make(map[string]repoState),
+		refs:          make(map[string]map[string]bool),
+	}
+
+	base := "https://" + host + "/your-ko/link-validator"
+	for _, link := range []string{base + "/issues/1", base + "/pull/2"} {
+		if err := proc.Process(context.Background(), link, "README.md"); !errors.Is(err, errs.ErrStatePolicy) {
+			t.Errorf("expected \n errors.Is(err, %v) to be true; \n got err=%v", errs.ErrStatePolicy, err)
+		}
+	}
+	// the policies are applied to the objects the handlers requested
+	for _, path := range []string{"/api/v3/repos/your-ko/link-validator/issues/1", "/api/v3/repos/your-ko/link-validator/pulls/2"} {
+		if requests[path] != 1 {
+			t.Errorf("%s requested %d times, want 1", path, requests[path])
+		}
+	}
+}
@@ -10,12 +10,16 @@ import (
 	"context"
 	"fmt"
 	"link-validator/pkg/config"
+	"link-validator/pkg/errs"
 	httpvalidator "link-validator/pkg/http"
 	"link-validator/pkg/regex"
 	"log/slog"
 	"net/http"
 	"net/url"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/v88/github"
 )
@@ -25,26 +29,28 @@ var handlers = map[string]handlerEntry{
 	"nope": {"nope", APIHandler{fn: handleNothing}},
 	"":     {"nope", APIHandler{fn: handleNothing}},
 
-	"blob":    {"contents", APIHandler{fn: handleContents}},
-	"tree":    {"contents", APIHandler{fn: handleContents}},
-	"raw":     {"contents", APIHandler{fn: handleContents}},
-	"blame":   {"contents", APIHandler{fn: handleContents}},
+	"blob":    {"contents", HTTPHandler{fn: handleContents}},
+	"tree":    {"contents", HTTPHandler{fn: handleContents}},
+	"raw":     {"contents", HTTPHandler{fn: handleContents}},
+	"blame":   {"contents", HTTPHandler{fn: handleContents}},
 	"compare": {"compareCommits", APIHandler{fn: handleCompareCommits}},
 
 	// Single-object routes
-	"commit":       {"commit", APIHandler{fn: handleCommit}},
-	"pull":         {"pull", APIHandler{fn: handlePull}},
-	"milestone":    {"milestone", APIHandler{fn: handleMilestone}},
-	"advisories":   {"advisories", APIHandler{fn: handleSecurityAdvisories}},
-	"commits":      {"commit", APIHandler{fn: handleCommit}},
-	"actions":      {"actions", APIHandler{fn: handleWorkflow}},
-	"user":         {"user", APIHandler{fn: handleUser}},
-	"issues":       {"issues", APIHandler{fn: handleIssue}},
-	"releases":     {"releases", APIHandler{fn: handleReleases}},
-	"labels":       {"labels", APIHandler{fn: handleLabel}},
-	"gist":         {"gist", APIHandler{fn: handleGist}},
-	"environments": {"environments", APIHandler{fn: handleEnvironments}},
-	"teams":        {"teams", APIHandler{fn: handleTeams}},
+	"commit":        {"commit", APIHandler{fn: handleCommit}},
+	"pull":          {"pull", HTTPHandler{fn: handlePull}},
+	"milestone":     {"milestone", APIHandler{fn: handleMilestone}},
+	"advisories":    {"advisories", APIHandler{fn: handleSecurityAdvisories}},
+	"commits":       {"commit", APIHandler{fn: handleCommit}},
+	"actions":       {"actions", APIHandler{fn: handleWorkflow}},
+	"user":          {"user", APIHandler{fn: handleUser}},
+	"issues":        {"issues", HTTPHandler{fn: handleIssue}},
+	"releases":      {"releases", APIHandler{fn: handleReleases}},
+	"releases.atom": {"repo-exist", APIHandler{fn: handleRepoExist}},
+	"labels":        {"labels", APIHandler{fn: handleLabel}},
+	"gist":          {"gist", APIHandler{fn: handleGist}},
+	"api":           {"api", APIHandler{fn: handleAPI}},
+	"environments":  {"environments", APIHandler{fn: handleEnvironments}},
+	"teams":         {"teams", APIHandler{fn: handleTeams}},
 
 	// Generic lists  — we just validate the repo exists
 	"repo":         {"repo-exist", APIHandler{fn: handleRepoExist}},
@@ -60,61 +66,148 @@ var handlers = map[string]handlerEntry{
 	"orgs":         {"org-exist", APIHandler{fn: handleOrgExist}},
 	"attestations": {"http", HTTPHandler{fn: handleHttp}}, // HTTP-based validation
 	"wiki":         {"http", HTTPHandler{fn: handleHttp}}, // HTTP-based validation
-	"projects":     {"http", HTTPHandler{fn: handleHttp}}, // HTTP-based validation
-	"discussions":  {"http", HTTPHandler{fn: handleHttp}}, // not available via GitHub API
 	"assets":       {"http", HTTPHandler{fn: handleHttp}}, // CDN assets, HTTP-only
+
+	// not available via REST API — validated via GraphQL, via HTTP without a token
+	"projects":    {"projects", HTTPHandler{fn: handleProjects}},
+	"discussions": {"discussions", HTTPHandler{fn: handleDiscussions}},
 }
 
 type LinkProcessor struct {
-	corpGitHubUrl string
-	corpClient    *wrapper
-	client        *wrapper
-	httpClient    *http.Client
+	// hosts are the clients of GitHub Enterprise instances by host name, e.g. 'github.mycorp.com'
+	hosts map[string]*wrapper
+	// hostLinks captures the links to the configured hosts, which don't look like 'github.[domain]'. nil if there are none
+	hostLinks  *regexp.Regexp
+	client     *wrapper
+	httpClient *http.Client
+	cache      *etagCache
+	// graphQL enables resolving the links in batched GraphQL queries, see Prefetch
+	graphQL    bool
+	resolved   map[graphQLKey]bool
+	permalinks config.PermalinksConfig
+	// statePolicies configures the policies for the state of linked issues and pull requests, see checkState
+	statePolicies config.StatePoliciesConfig
+	// repoStates caches the repository states by 'host/owner/repo', see checkRepository
+	repoStates map[string]repoState
+	// refs caches the branches and tags by 'host/owner/repo:prefix', see refCandidates
+	refs map[string]map[string]bool
 }
 
 func New(cfg *config.Config) (*LinkProcessor, error) {
 	httpClient := httpvalidator.InitHttpClient(cfg)
 
-	opts := []github.ClientOptionsFunc{github.WithHTTPClient(httpClient)}
-	if cfg.Validators.GitHub.PAT != "" {
-		opts = append(opts, github.WithAuthToken(cfg.Validators.GitHub.PAT))
-	}
-	client, err := github.NewClient(opts...)
+	// API responses are cached for conditional requests, the cache is shared because the keys are full urls
+	cache := newETagCache(cfg.Validators.GitHub.CacheFile)
+	apiHttpClient := *httpClient
+	apiHttpClient.Transport = cache.transport(httpClient.Transport)
+
+	limiter := newRateLimiter("github.com", cfg.Validators.GitHub.RateLimitBudget())
+	client, err := newClient("https://api.github.com/", "https://uploads.github.com/",
+		cfg.Validators.GitHub.PAT, cfg.Validators.GitHub.App, limiter.transport(&apiHttpClient))
 	if err != nil {
 		return nil, err
 	}
-	if cfg.Validators.GitHub.CorpGitHubUrl == "" {
-		return &LinkProcessor{
-			client:     &wrapper{client},
-			httpClient: httpClient,
-		}, nil
+	proc := &LinkProcessor{
+		hosts:         make(map[string]*wrapper),
+		client:        &wrapper{client: client, limiter: limiter},
+		httpClient:    httpClient,
+		cache:         cache,
+		graphQL:       cfg.Validators.GitHub.IsGraphQL(),
+		resolved:      make(map[graphQLKey]bool),
+		permalinks:    cfg.Validators.GitHub.Permalinks,
+		statePolicies: cfg.Validators.GitHub.StatePolicies,
+		repoStates:    make(map[string]repoState),
+		refs:          make(map[string]map[string]bool),
+	}
+
+	// CORP_URL/CORP_PAT is the shortcut for a single GitHub Enterprise instance
+	if corpUrl := cfg.Validators.GitHub.CorpGitHubUrl; corpUrl != "" {
+		host := config.GitHubHost{Url: corpUrl}
+		if _, err := host.Hostname(); err != nil {
+			return nil, fmt.Errorf("invalid enterprise url: '%s'", corpUrl)
+		}
+		if err := proc.addHost(host, cfg.Validators.GitHub.CorpPAT, &apiHttpClient, cfg.Validators.GitHub.RateLimitBudget()); err != nil {
+			return nil, err
+		}
+	}
+	for _, host := range cfg.Validators.GitHub.Hosts {
+		token := config.GetEnv(host.TokenEnv, "")
+		if token == "" && !host.App.IsSet() {
+			slog.Warn("github: the token of the GitHub host is not set", slog.String("host", host.Url), slog.String("env", host.TokenEnv))
+		}
+		if err := proc.addHost(host, token, &apiHttpClient, cfg.Validators.GitHub.RateLimitBudget()); err != nil {
+			return nil, err
+		}
+	}
+
+	hostnames := make([]string, 0, len(proc.hosts))
+	for hostname := range proc.hosts {
+		hostnames = append(hostnames, hostname)
 	}
+	proc.hostLinks = hostLinks(hostnames)
+	return proc, nil
+}
 
-	// Derive the bare host from baseUrl, e.g. "github.mycorp.com"
-	u, err := url.Parse(cfg.Validators.GitHub.CorpGitHubUrl)
-	if err != nil || u.Hostname() == "" {
-		return nil, fmt.Errorf("invalid enterprise url: '%s'", cfg.Validators.GitHub.CorpGitHubUrl)
+// addHost creates the client of the GitHub Enterprise instance
+func (proc *LinkProcessor) addHost(host config.GitHubHost, token string, httpClient *http.Client, rateLimitWait time.Duration) error {
+	hostname, err := host.Hostname()
+	if err != nil {
+		return err
+	}
+	if _, ok := proc.hosts[hostname]; ok {
+		return fmt.Errorf("GitHub host '%s' is configured more than once", hostname)
 	}
-	host := fmt.Sprintf("%s://%s", u.Scheme, u.Hostname())
-	opts = []github.ClientOptionsFunc{github.WithHTTPClient(httpClient), github.WithEnterpriseURLs(
-		host,
-		strings.ReplaceAll(host, "https://", "https://uploads."),
-	)}
-	if cfg.Validators.GitHub.CorpPAT != "" {
-		opts = append(opts, github.WithAuthToken(cfg.Validators.GitHub.CorpPAT))
+	u, _ := url.Parse(host.Url)
+	prefix := host.ApiPrefix
+	if prefix == "" {
+		prefix = "/api/v3"
 	}
+	baseURL := fmt.Sprintf("%s://%s%s/", u.Scheme, u.Host, strings.TrimSuffix(prefix, "/"))
+	uploadURL := fmt.Sprintf("%s://%s/api/uploads/", u.Scheme, u.Host)
 
-	corpClient, err := github.NewClient(opts...)
+	limiter := newRateLimiter(hostname, rateLimitWait)
+	client, err := newClient(baseURL, uploadURL, token, host.App, limiter.transport(httpClient))
 	if err != nil {
-		return nil, fmt.Errorf("can't create GitHub Processor: %s", err)
+		return fmt.Errorf("can't create GitHub Processor: %s", err)
 	}
+	proc.hosts[hostname] = &wrapper{client: client, limiter: limiter}
+	return nil
+}
 
-	return &LinkProcessor{
-		corpGitHubUrl: u.Hostname(),
-		corpClient:    &wrapper{corpClient},
-		client:        &wrapper{client},
-		httpClient:    httpClient,
-	}, nil
+// newClient creates the GitHub API client authenticated either as the GitHub App installation or with the token
+func newClient(baseURL, uploadURL, token string, app config.GitHubAppConfig, httpClient *http.Client) (*github.Client, error) {
+	opts := []github.ClientOptionsFunc{github.WithURLs(&baseURL, &uploadURL), github.WithHTTPClient(httpClient)}
+	switch {
+	case app.IsSet():
+		transport, err := newAppTransport(httpClient.Transport, app, baseURL)
+		if err != nil {
+			return nil, err
+		}
+		if token != "" {
+			slog.Info("github: both token and GitHub App are configured, using GitHub App", slog.String("url", baseURL))
+		}
+		opts = append(opts, github.WithTransport(transport))
+	case token != "":
+		opts = append(opts, github.WithAuthToken(token))
+	}
+	return github.NewClient(opts...)
+}
+
+// hostLinks returns the regex capturing the links to the hosts, which are not captured by regex.GitHub.
+// Returns nil if there are no such hosts.
+func hostLinks(hostnames []string) *regexp.Regexp {
+	var quoted []string
+	for _, hostname := range hostnames {
+		if !regex.GitHub.MatchString("https://" + hostname) {
+			quoted = append(quoted, regexp.QuoteMeta(hostname))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+	sort.Strings(quoted)
+	return regexp.MustCompile(`(?i)https://(?:gist\.|raw\.)?(?:` + strings.Join(quoted, "|") +
+		`)(?::[0-9]+)?(?:/[^\s\x60\]~"\\<>]*[^\s.,:;!?()\[\]{}\x60~"\\<>])?`)
 }
 
 type ghURL struct {
@@ -127,6 +220,11 @@ type ghURL struct {
 	path       string
 	anchor     string
 	url        string
+	// query of the web url, e.g. 'plain=1' shows Markdown files as plain text, nil if there is none
+	query url.Values
+	// issue and pull are set by the handlers, the state policies are applied to them
+	issue *github.Issue
+	pull  *github.PullRequest
 }
 
 func (proc *LinkProcessor) Process(ctx context.Context, url string, _ string) error {
@@ -137,12 +235,19 @@ func (proc *LinkProcessor) Process(ctx context.Context, url string, _ string) er
 		return err
 	}
 
-	if gh.enterprise && proc.corpGitHubUrl == "" {
-		return fmt.Errorf("the url '%s' looks like a corp url, but CORP_URL is not set", url)
+	client := proc.clientFor(gh)
+	if client == nil {
+		return fmt.Errorf("the url '%s' looks like a GitHub Enterprise url, but its host is not configured (CORP_URL or validators.github.hosts)", url)
 	}
-	client := proc.client
-	if proc.corpGitHubUrl == strings.TrimPrefix(gh.host, "gist.") {
-		client = proc.corpClient
+	if key, ok := graphQLKeyOf(gh); ok {
+		if exists, resolved := proc.resolved[key]; resolved {
+			slog.Debug("github: resolved via GraphQL", slog.String("url", url))
+			if !exists {
+				return errs.NewNotFound(url)
+			}
+			// the state of the repository is prefetched as well
+			return proc.checkRepository(ctx, client, gh)
+		}
 	}
 	entry, ok := handlers[gh.typ]
 	if !ok {
@@ -150,7 +255,66 @@ func (proc *LinkProcessor) Process(ctx context.Context, url string, _ string) er
 	}
 	slog.Debug("github: using", slog.String("handler", entry.name))
 
-	return mapGHError(url, entry.handler.Handle(ctx, client, proc.httpClient, gh))
+	for {
+		if client.limiter.exhausted {
+			client.limiter.unverified++
+			return errs.NewRateLimited(url)
+		}
+		var resolved *ghURL
+		resolved, err = proc.handle(ctx, client, entry.handler, gh)
+		if err == nil {
+			err = proc.checkPermalink(ctx, client, resolved)
+		}
+		if err == nil {
+			err = proc.checkState(resolved)
+		}
+		if err == nil {
+			err = proc.checkRepository(ctx, client, resolved)
+		}
+		if !client.limiter.retry(ctx, err) {
+			return mapGHError(url, err)
+		}
+	}
+}
+
+// Close persists the cache of GitHub API responses
+func (proc *LinkProcessor) Close() error {
+	if proc.cache == nil {
+		return nil
+	}
+	if proc.cache.hits > 0 {
+		slog.Info("GitHub API responses not modified since the previous requests", slog.Int("requests", proc.cache.hits))
+	}
+	return proc.cache.save()
+}
+
+// clientFor returns the client for the url host, nil if the url looks like a GitHub Enterprise url, but the host is not configured
+func (proc *LinkProcessor) clientFor(gh *ghURL) *wrapper {
+	u := url.URL{Host: gh.host}
+	hostname := strings.ToLower(u.Hostname())
+	for _, prefix := range []string{"gist.", "raw."} {
+		hostname = strings.TrimPrefix(hostname, prefix)
+	}
+	if client, ok := proc.hosts[hostname]; ok {
+		return client
+	}
+	if gh.enterprise {
+		return nil
+	}
+	return proc.client
+}
+
+// ReportQuota logs the GitHub API quota summary
+func (proc *LinkProcessor) ReportQuota() {
+	proc.client.limiter.report()
+	hostnames := make([]string, 0, len(proc.hosts))
+	for hostname := range proc.hosts {
+		hostnames = append(hostnames, hostname)
+	}
+	sort.Strings(hostnames)
+	for _, hostname := range hostnames {
+		proc.hosts[hostname].limiter.report()
+	}
 }
 
 // TODO: refactor me
@@ -168,6 +332,9 @@ func parseUrl(link string) (*ghURL, error) {
 		anchor:     u.Fragment,
 		url:        link,
 	}
+	if u.RawQuery != "" {
+		gh.query = u.Query()
+	}
 
 	// Handle root GitHub URL
 	if len(parts) <= 1 && parts[0] == "" {
@@ -181,9 +348,31 @@ func parseUrl(link string) (*ghURL, error) {
 		parts = append(parts, make([]string, diff)...)[:growTo]
 	}
 
+	// REST API and raw content urls are validated with the same authenticated client as the web urls
+	hostname := strings.ToLower(u.Hostname())
+	public := hostname == "github.com" || hostname == "gist.github.com"
+	switch {
+	case hostname == "api.github.com":
+		return parseApiUrl(gh, u, parts), nil
+	case !public && parts[0] == "api" && parts[1] == "v3":
+		return parseApiUrl(gh, u, parts[2:]), nil
+	case hostname == "raw.githubusercontent.com" || strings.HasPrefix(hostname, "raw."):
+		return parseRawUrl(gh, parts), nil
+	case !public && parts[0] == "raw":
+		// GitHub Enterprise without subdomain isolation serves raw content from 'https://[host]/raw/'
+		return parseRawUrl(gh, parts[1:]), nil
+	}
+
 	// Handle org urls
 	switch parts[0] {
 	case "organizations", "orgs":
+		if parts[2] == "projects" && parts[3] != "" {
+			gh.typ = "projects"
+			gh.owner = parts[1]
+			gh.ref = parts[3]
+			gh.path = joinPath(parts[4:])
+			return gh, nil
+		}
 		if parts[2] != "teams" {
 			gh.typ = "orgs"
 			gh.owner = parts[1]
@@ -225,19 +414,28 @@ func parseUrl(link string) (*ghURL, error) {
 		} else {
 			gh.typ = "repo"
 		}
-	case "branches", "tags", "labels", "pulls", "milestones", "search":
+	case "branches", "tags", "pulls", "milestones", "search", "releases.atom":
 	// these above go to simple 'if repo exists' validation
+	case "labels":
+		// the label is taken from the escaped path, a label with a slash ('kind%2Fbug') is a single segment there
+		escaped := strings.Split(strings.TrimPrefix(u.EscapedPath(), "/"), "/")
+		if len(escaped) > 3 {
+			if name, err := url.PathUnescape(escaped[3]); err == nil {
+				gh.ref = name
+			}
+		}
 	case "blob", "tree", "blame", "raw":
 		gh.ref = parts[3]
 		gh.path = joinPath(parts[4:])
 	case "releases":
 		switch parts[3] {
-		case "tag", "download":
+		case "tag", "download", "edit", "expanded_assets":
 			gh.ref = parts[3]
 			gh.path = joinPath(parts[4:])
 		default:
+			// 'latest', 'latest/download/<asset>', 'new'
 			gh.ref = ""
-			gh.path = parts[3]
+			gh.path = joinPath(parts[3:])
 		}
 	case "packages", "pkgs":
 		if gh.typ == "pkgs" {
@@ -249,7 +447,7 @@ func parseUrl(link string) (*ghURL, error) {
 			gh.path = joinPath(parts[3:])
 		}
 	case "discussions", "wiki", "projects", "assets":
-		// not available via GitHub API — validated via HTTP.
+		// not available via REST API — validated via GraphQL or HTTP.
 		gh.ref = parts[3]
 		gh.path = joinPath(parts[4:])
 	case "commit", "commits", "issues", "pull",
@@ -269,7 +467,17 @@ func parseUrl(link string) (*ghURL, error) {
 		gh.typ = parts[2]
 		gh.ref = parts[3]
 	case "gist":
-		gh.ref = parts[2]
+		if parts[2] != "raw" {
+			gh.ref = parts[2] // revision
+			break
+		}
+		// raw files 'raw/<file>' and 'raw/<revision>/<file>', gist.githubusercontent.com serves them
+		if parts[4] == "" {
+			gh.path = parts[3]
+		} else {
+			gh.ref = parts[3]
+			gh.path = joinPath(parts[4:])
+		}
 	case "security":
 		// I validate only 'advisories' existence, the rest is goes by 'handleRepoExist'
 		if parts[3] == "advisories" && parts[4] != "" {
@@ -283,6 +491,37 @@ func parseUrl(link string) (*ghURL, error) {
 	return gh, nil
 }
 
+// parseApiUrl parses the GitHub REST API urls, e.g. 'https://api.github.com/repos/owner/repo/issues/1'.
+// The path relative to the API root is requested as is, parts don't contain the API prefix.
+func parseApiUrl(gh *ghURL, u *url.URL, parts []string) *ghURL {
+	gh.typ = "api"
+	gh.path = joinPath(parts)
+	if u.RawQuery != "" {
+		gh.path += "?" + u.RawQuery
+	}
+	if parts[0] == "repos" {
+		gh.owner, gh.repo = parts[1], parts[2]
+	}
+	return gh
+}
+
+// parseRawUrl parses the raw content urls, e.g. 'https://raw.githubusercontent.com/owner/repo/ref/path'.
+// The ref might be fully qualified, e.g. 'refs/heads/main'.
+func parseRawUrl(gh *ghURL, parts []string) *ghURL {
+	gh.owner, gh.repo = parts[0], parts[1]
+	if gh.owner == "" || gh.repo == "" {
+		gh.typ = "nope"
+		return gh
+	}
+	gh.typ = "raw"
+	if parts[2] == "refs" && (parts[3] == "heads" || parts[3] == "tags") {
+		parts = parts[2:]
+	}
+	gh.ref = parts[2]
+	gh.path = joinPath(parts[3:])
+	return gh
+}
+
 func joinPath(parts []string) string {
 	i := 0
 	for ; i < len(parts) && parts[i] != ""; i++ {
@@ -298,6 +537,9 @@ func joinPath(parts []string) string {
 
 func (proc *LinkProcessor) ExtractLinks(line string) []string {
 	parts := regex.GitHub.FindAllString(line, -1)
+	if proc.hostLinks != nil {
+		parts = append(parts, proc.hostLinks.FindAllString(line, -1)...)
+	}
 	if len(parts) == 0 {
 		return nil
 	}
@@ -326,7 +568,7 @@ func (proc *LinkProcessor) Excludes(url string) bool {
 	if strings.HasPrefix(url, "https://github.com/features") {
 		return false
 	}
-	// github.blog, docs.github.com, raw.githubusercontent.com don't match
+	// github.blog, docs.github.com don't match
 	// regex.GitHub so they're already handled by the HTTP processor
-	return regex.GitHub.MatchString(url)
+	return regex.GitHub.MatchString(url) || (proc.hostLinks != nil && proc.hostLinks.MatchString(url))
 }
@@ -2,6 +2,7 @@ package github
 
 import (
 	"link-validator/pkg/config"
+	"net/url"
 	"reflect"
 	"testing"
 )
@@ -82,7 +83,6 @@ func TestInternalLinkProcessor_ExtractLinks(t *testing.T) {
 			name: "ignores non-API GitHub links",
 			line: `test
 				https://github.com/features/preview
-				https://raw.githubusercontent.com/your-ko/link-validator/refs/heads/main/README.md
 				https://github.blog/news-insights/product-news/lets-talk-about-github-actions
 				https://docs.github.com/en/actions
 				test`,
@@ -129,13 +129,20 @@ func TestInternalLinkProcessor_ExtractLinks(t *testing.T) {
 			},
 		},
 		{
-			name: "ignores non-api calls",
+			name: "captures raw content and REST API urls, ignores uploads",
 			line: `
 				https://raw.githubusercontent.com/your-ko/link-validator/refs/heads/main/README.md
 				https://uploads.github.mycorp.com/org/repo/raw/main/img.png
 				https://api.github.com/repos/your-ko/link-validator/contents/?ref=a96366f66ffacd461de10a1dd561ab5a598e9167
+				https://raw.github.mycorp.com/your-ko/link-validator/main/README.md
+				https://github.mycorp.com/api/v3/repos/your-ko/link-validator
 				`,
-			want: nil,
+			want: []string{
+				"https://raw.githubusercontent.com/your-ko/link-validator/refs/heads/main/README.md",
+				"https://api.github.com/repos/your-ko/link-validator/contents/?ref=a96366f66ffacd461de10a1dd561ab5a598e9167",
+				"https://raw.github.mycorp.com/your-ko/link-validator/main/README.md",
+				"https://github.mycorp.com/api/v3/repos/your-ko/link-validator",
+			},
 		},
 		{
 			name: "captures refs urls",
@@ -192,10 +199,12 @@ func TestInternalLinkProcessor_ExtractLinks(t *testing.T) {
 			line: `
 				Check out this gist: https://gist.github.com/your-ko/e7ed9b8d381113399342e3d6953d9da7
 				And this one: https://gist.github.com/user/123456789abcdef
+				Raw file: https://gist.githubusercontent.com/user/123456789abcdef/raw/install.sh
 			`,
 			want: []string{
 				"https://gist.github.com/your-ko/e7ed9b8d381113399342e3d6953d9da7",
 				"https://gist.github.com/user/123456789abcdef",
+				"https://gist.githubusercontent.com/user/123456789abcdef/raw/install.sh",
 			},
 		},
 		{
@@ -424,6 +433,21 @@ func TestInternalLinkProcessor_ParseGitHubUrl(t *testing.T) {
 				url:    "https://github.com/your-ko/link-validator/blob/main/README.md#features",
 			},
 		},
+		{
+			name: "repo url blob with line anchor in plain view",
+			url:  "https://github.com/your-ko/link-validator/blob/main/README.md?plain=1#L10-L25",
+			want: &ghURL{
+				host:   "github.com",
+				owner:  "your-ko",
+				repo:   "link-validator",
+				typ:    "blob",
+				ref:    "main",
+				path:   "README.md",
+				anchor: "L10-L25",
+				url:    "https://github.com/your-ko/link-validator/blob/main/README.md?plain=1#L10-L25",
+				query:  url.Values{"plain": {"1"}},
+			},
+		},
 		{
 			name: "Referencing a file in a particular commit",
 			url:  "https://github.com/your-ko/link-validator/blob/83e43288254d0f36e723ef2cf3328b8b77836560/README.md",
@@ -533,6 +557,55 @@ func TestInternalLinkProcessor_ParseGitHubUrl(t *testing.T) {
 				url:   "https://github.com/your-ko/link-validator/releases/download/1.0.0/sbom.spdx.json",
 			},
 		},
+		{
+			name: "release: download artifact of the latest release",
+			url:  "https://github.com/your-ko/link-validator/releases/latest/download/link-validator_linux_amd64.tar.gz",
+			want: &ghURL{
+				host:  "github.com",
+				owner: "your-ko",
+				repo:  "link-validator",
+				typ:   "releases",
+				path:  "latest/download/link-validator_linux_amd64.tar.gz",
+				url:   "https://github.com/your-ko/link-validator/releases/latest/download/link-validator_linux_amd64.tar.gz",
+			},
+		},
+		{
+			name: "release: edit",
+			url:  "https://github.com/your-ko/link-validator/releases/edit/1.0.0",
+			want: &ghURL{
+				host:  "github.com",
+				owner: "your-ko",
+				repo:  "link-validator",
+				typ:   "releases",
+				ref:   "edit",
+				path:  "1.0.0",
+				url:   "https://github.com/your-ko/link-validator/releases/edit/1.0.0",
+			},
+		},
+		{
+			name: "release: expanded assets",
+			url:  "https://github.com/your-ko/link-validator/releases/expanded_assets/1.0.0",
+			want: &ghURL{
+				host:  "github.com",
+				owner: "your-ko",
+				repo:  "link-validator",
+				typ:   "releases",
+				ref:   "expanded_assets",
+				path:  "1.0.0",
+				url:   "https://github.com/your-ko/link-validator/releases/expanded_assets/1.0.0",
+			},
+		},
+		{
+			name: "releases feed",
+			url:  "https://github.com/your-ko/link-validator/releases.atom",
+			want: &ghURL{
+				host:  "github.com",
+				owner: "your-ko",
+				repo:  "link-validator",
+				typ:   "releases.atom",
+				url:   "https://github.com/your-ko/link-validator/releases.atom",
+			},
+		},
 		{
 			name: "issues url",
 			url:  "https://github.com/your-ko/link-validator/issues",
@@ -697,6 +770,7 @@ func TestInternalLinkProcessor_ParseGitHubUrl(t *testing.T) {
 				repo:  "link-validator",
 				typ:   "search",
 				url:   "https://github.com/your-ko/link-validator/search?q=blah",
+				query: url.Values{"q": {"blah"}},
 			},
 		},
 
@@ -735,13 +809,36 @@ func TestInternalLinkProcessor_ParseGitHubUrl(t *testing.T) {
 			},
 		},
 		{
-			name: "repo url to a particular project",
-			url:  "https://github.com/orgs/your-ko/projects/1",
+			name: "org url to a project view",
+			url:  "https://github.com/orgs/your-ko/projects/1/views/2",
+			want: &ghURL{
+				host:  "github.com",
+				owner: "your-ko",
+				typ:   "projects",
+				ref:   "1",
+				path:  "views/2",
+				url:   "https://github.com/orgs/your-ko/projects/1/views/2",
+			},
+		},
+		{
+			name: "org url to projects",
+			url:  "https://github.com/orgs/your-ko/projects",
 			want: &ghURL{
 				host:  "github.com",
 				owner: "your-ko",
 				typ:   "orgs",
-				path:  "projects/1",
+				path:  "projects",
+				url:   "https://github.com/orgs/your-ko/projects",
+			},
+		},
+		{
+			name: "org url to a particular project",
+			url:  "https://github.com/orgs/your-ko/projects/1",
+			want: &ghURL{
+				host:  "github.com",
+				owner: "your-ko",
+				typ:   "projects",
+				ref:   "1",
 				url:   "https://github.com/orgs/your-ko/projects/1",
 			},
 		},
@@ -957,9 +1054,22 @@ func TestInternalLinkProcessor_ParseGitHubUrl(t *testing.T) {
 				owner: "your-ko",
 				repo:  "link-validator",
 				typ:   "labels",
+				ref:   "test",
 				url:   "https://github.com/your-ko/link-validator/labels/test",
 			},
 		},
+		{
+			name: "repo url label with a slash",
+			url:  "https://github.com/your-ko/link-validator/labels/kind%2Fbug",
+			want: &ghURL{
+				host:  "github.com",
+				owner: "your-ko",
+				repo:  "link-validator",
+				typ:   "labels",
+				ref:   "kind/bug",
+				url:   "https://github.com/your-ko/link-validator/labels/kind%2Fbug",
+			},
+		},
 		{
 			name: "github user",
 			url:  "https://github.com/your-ko",
@@ -1088,6 +1198,7 @@ func TestInternalLinkProcessor_ParseGitHubUrl(t *testing.T) {
 				ref:   "container",
 				path:  "link-validator/617266022",
 				url:   "https://github.com/your-ko/link-validator/pkgs/container/link-validator/617266022?tag=1.18.1",
+				query: url.Values{"tag": {"1.18.1"}},
 			},
 		},
 		{
@@ -1101,6 +1212,7 @@ func TestInternalLinkProcessor_ParseGitHubUrl(t *testing.T) {
 				ref:   "container",
 				path:  "link-validator/617266022",
 				url:   "https://github.com/your-ko/link-validator/pkgs/container/link-validator/617266022?tag=sha256-85ea5aa29b291cb9213040fc6b6661657ec61dc4",
+				query: url.Values{"tag": {"sha256-85ea5aa29b291cb9213040fc6b6661657ec61dc4"}},
 			},
 		},
 		{
@@ -1199,6 +1311,43 @@ func TestInternalLinkProcessor_ParseGitHubUrl(t *testing.T) {
 				url:   "https://gist.github.com/your-ko/e7ed9b8d381113399342e3d6953d9da7/ad9fe8be4adf55922e36904e6cc7745e83d10d10",
 			},
 		},
+		{
+			name: "raw gist file",
+			url:  "https://gist.githubusercontent.com/your-ko/e7ed9b8d381113399342e3d6953d9da7/raw/README.md",
+			want: &ghURL{
+				host:  "gist.githubusercontent.com",
+				owner: "your-ko",
+				repo:  "e7ed9b8d381113399342e3d6953d9da7",
+				typ:   "gist",
+				path:  "README.md",
+				url:   "https://gist.githubusercontent.com/your-ko/e7ed9b8d381113399342e3d6953d9da7/raw/README.md",
+			},
+		},
+		{
+			name: "raw gist file of a revision",
+			url:  "https://gist.github.com/your-ko/e7ed9b8d381113399342e3d6953d9da7/raw/ad9fe8be4adf55922e36904e6cc7745e83d10d10/README.md",
+			want: &ghURL{
+				host:  "gist.github.com",
+				owner: "your-ko",
+				repo:  "e7ed9b8d381113399342e3d6953d9da7",
+				typ:   "gist",
+				ref:   "ad9fe8be4adf55922e36904e6cc7745e83d10d10",
+				path:  "README.md",
+				url:   "https://gist.github.com/your-ko/e7ed9b8d381113399342e3d6953d9da7/raw/ad9fe8be4adf55922e36904e6cc7745e83d10d10/README.md",
+			},
+		},
+		{
+			name: "gist.github.com with file fragment",
+			url:  "https://gist.github.com/your-ko/e7ed9b8d381113399342e3d6953d9da7#file-readme-md",
+			want: &ghURL{
+				host:   "gist.github.com",
+				owner:  "your-ko",
+				repo:   "e7ed9b8d381113399342e3d6953d9da7",
+				typ:    "gist",
+				anchor: "file-readme-md",
+				url:    "https://gist.github.com/your-ko/e7ed9b8d381113399342e3d6953d9da7#file-readme-md",
+			},
+		},
 		{
 			name: "gist.github.com with comment fragment",
 			url:  "https://gist.github.com/your-ko/e7ed9b8d381113399342e3d6953d9da7#gistcomment-12345",
@@ -1283,12 +1432,106 @@ func TestInternalLinkProcessor_ParseGitHubUrl(t *testing.T) {
 			},
 		},
 		{
-			// api.github.com URLs are not captured by ExtractLinks (regex.GitHub doesn't match them)
-			// and are handled directly by the HTTP processor instead.
-			name:    "github api url (not handled by GitHub processor)",
-			url:     "https://api.github.com/repos/your-ko/link-validator/milestones/1",
-			want:    nil,
-			wantErr: true,
+			name: "github api url",
+			url:  "https://api.github.com/repos/your-ko/link-validator/milestones/1",
+			want: &ghURL{
+				host:  "api.github.com",
+				owner: "your-ko",
+				repo:  "link-validator",
+				typ:   "api",
+				path:  "repos/your-ko/link-validator/milestones/1",
+				url:   "https://api.github.com/repos/your-ko/link-validator/milestones/1",
+			},
+		},
+		{
+			name: "github api url with query",
+			url:  "https://api.github.com/repos/your-ko/link-validator/contents/?ref=a96366f",
+			want: &ghURL{
+				host:  "api.github.com",
+				owner: "your-ko",
+				repo:  "link-validator",
+				typ:   "api",
+				path:  "repos/your-ko/link-validator/contents?ref=a96366f",
+				url:   "https://api.github.com/repos/your-ko/link-validator/contents/?ref=a96366f",
+				query: url.Values{"ref": {"a96366f"}},
+			},
+		},
+		{
+			name: "github api url, not a repository",
+			url:  "https://api.github.com/users/your-ko",
+			want: &ghURL{
+				host: "api.github.com",
+				typ:  "api",
+				path: "users/your-ko",
+				url:  "https://api.github.com/users/your-ko",
+			},
+		},
+		{
+			name: "enterprise github api url",
+			url:  "https://github.mycorp.com/api/v3/repos/your-ko/link-validator/issues/1",
+			want: &ghURL{
+				enterprise: true,
+				host:       "github.mycorp.com",
+				owner:      "your-ko",
+				repo:       "link-validator",
+				typ:        "api",
+				path:       "repos/your-ko/link-validator/issues/1",
+				url:        "https://github.mycorp.com/api/v3/repos/your-ko/link-validator/issues/1",
+			},
+		},
+		{
+			name: "raw content url",
+			url:  "https://raw.githubusercontent.com/your-ko/link-validator/main/docs/README.md",
+			want: &ghURL{
+				host:  "raw.githubusercontent.com",
+				owner: "your-ko",
+				repo:  "link-validator",
+				typ:   "raw",
+				ref:   "main",
+				path:  "docs/README.md",
+				url:   "https://raw.githubusercontent.com/your-ko/link-validator/main/docs/README.md",
+			},
+		},
+		{
+			name: "raw content url with fully qualified ref",
+			url:  "https://raw.githubusercontent.com/your-ko/link-validator/refs/heads/main/README.md",
+			want: &ghURL{
+				host:  "raw.githubusercontent.com",
+				owner: "your-ko",
+				repo:  "link-validator",
+				typ:   "raw",
+				ref:   "main",
+				path:  "README.md",
+				url:   "https://raw.githubusercontent.com/your-ko/link-validator/refs/heads/main/README.md",
+			},
+		},
+		{
+			name: "enterprise raw content url",
+			url:  "https://raw.github.mycorp.com/your-ko/link-validator/v1.0.0/README.md",
+			want: &ghURL{
+				enterprise: true,
+				host:       "raw.github.mycorp.com",
+				owner:      "your-ko",
+				repo:       "link-validator",
+				typ:        "raw",
+				ref:        "v1.0.0",
+				path:       "README.md",
+				url:        "https://raw.github.mycorp.com/your-ko/link-validator/v1.0.0/README.md",
+			},
+		},
+		{
+			name: "enterprise raw content url without subdomain isolation",
+			url:  "https://github.mycorp.com/raw/your-ko/link-validator/main/README.md",
+			want: &ghURL{
+				enterprise: true,
+				host:       "github.mycorp.com",
+				owner:      "your-ko",
+				repo:       "link-validator",
+				typ:        "raw",
+				ref:        "main",
+				path:       "README.md",
+				url:        "https://github.mycorp.com/raw/your-ko/link-validator/main/README.md",
+			},
 		},
 		{
 			name: "github release download (assets) url",
@@ -1412,3 +1655,62 @@ func Test_joinPath(t *testing.T) {
 		})
 	}
 }
+
+func TestNew_hosts(t *testing.T) {
+	t.Setenv("ACQUIRED_PAT", "acquired-pat")
+	cfg := &config.Config{
+		Validators: config.ValidatorsConfig{
+			GitHub: config.GitHubConfig{
+				CorpGitHubUrl: "https://github.mycorp.com",
+				CorpPAT:       "corp-pat",
+				Hosts: []config.GitHubHost{
+					{Url: "https://code.acquired.io", TokenEnv: "ACQUIRED_PAT", ApiPrefix: "/github/api/v3"},
+					{Url: "https://github.other.com:8443", TokenEnv: "OTHER_PAT"},
+				},
+			},
+		},
+	}
+	p, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		link        string
+		wantBaseURL string
+	}{
+		{link: "https://github.com/your-ko/link-validator", wantBaseURL: "https://api.github.com/"},
+		{link: "https://gist.github.com/your-ko/1", wantBaseURL: "https://api.github.com/"},
+		{link: "https://github.mycorp.com/your-ko/link-validator", wantBaseURL: "https://github.mycorp.com/api/v3/"},
+		{link: "https://code.acquired.io/your-ko/link-validator", wantBaseURL: "https://code.acquired.io/github/api/v3/"},
+		{link: "https://GitHub.Other.com:8443/your-ko/link-validator", wantBaseURL: "https://github.other.com:8443/api/v3/"},
+		{link: "https://github.unknown.com/your-ko/link-validator"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			gh, err := parseUrl(tt.link)
+			if err != nil {
+				t.Fatalf("parseUrl() error = %v", err)
+			}
+			client := p.clientFor(gh)
+			if tt.wantBaseURL == "" {
+				if client != nil {
+					t.Errorf("clientFor() = %s, want nil", client.client.BaseURL())
+				}
+				return
+			}
+			if client == nil || client.client.BaseURL() != tt.wantBaseURL {
+				t.Fatalf("clientFor() doesn't route to %s", tt.wantBaseURL)
+			}
+		})
+	}
+
+	line := "see https://code.acquired.io/your-ko/link-validator/blob/main/README.md and https://code.other.io/x/y"
+	want := []string{"https://code.acquired.io/your-ko/link-validator/blob/main/README.md"}
+	if got := p.ExtractLinks(line); !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractLinks() = %v, want %v", got, want)
+	}
+	if !p.Excludes("https://code.acquired.io/your-ko/link-validator") {
+		t.Errorf("Excludes() = false for the configured host")
+	}
+}
@@ -117,7 +117,7 @@ func TestExternalHttpLinkProcessor_ExtractLinks(t *testing.T) {
 			want: []string{},
 		},
 		{
-			name: "captures non-api calls",
+			name: "captures uploads, ignores raw content and REST API urls handled by the GitHub processor",
 			line: `
 				https://uploads.github.mycorp.com/org/repo/raw/main/img.png
 				https://raw.githubusercontent.com/your-ko/link-validator/refs/heads/main/README.md
@@ -125,8 +125,6 @@ func TestExternalHttpLinkProcessor_ExtractLinks(t *testing.T) {
 				`,
 			want: []string{
 				"https://uploads.github.mycorp.com/org/repo/raw/main/img.png",
-				"https://raw.githubusercontent.com/your-ko/link-validator/refs/heads/main/README.md",
-				"https://api.github.com/repos/your-ko/link-validator/contents/?ref=a96366f66ffacd461de10a1dd561ab5a598e9167",
 			},
 		},
 		{
@@ -189,13 +187,11 @@ func TestExternalHttpLinkProcessor_ExtractLinks(t *testing.T) {
 			name: "captures non-API GitHub links",
 			line: `test
 				https://github.com/features/preview
-				https://raw.githubusercontent.com/your-ko/link-validator/refs/heads/main/README.md
 				https://github.blog/news-insights/product-news/lets-talk-about-github-actions
 				https://docs.github.com/en/actions
 				test`,
 			want: []string{
 				"https://github.com/features/preview",
-				"https://raw.githubusercontent.com/your-ko/link-validator/refs/heads/main/README.md",
 				"https://github.blog/news-insights/product-news/lets-talk-about-github-actions",
 				"https://docs.github.com/en/actions",
 			},
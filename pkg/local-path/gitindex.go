@@ -0,0 +1,195 @@
+package local_path
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// gitIndex contains the paths tracked by git.
+// It is read directly from '.git/index' because the validator runs in a scratch image without git binary.
+type gitIndex struct {
+	files map[string]bool
+	dirs  map[string]bool
+	// gitlinks maps submodule paths to the pinned commit SHA
+	gitlinks map[string]string
+}
+
+const (
+	indexHeaderSize   = 12
+	indexEntrySize    = 62 // ctime, mtime, dev, ino, mode, uid, gid, size, sha1, flags
+	indexExtendedFlag = 0x4000
+	indexNameMask     = 0x0fff
+	modeTypeMask      = 0o170000
+	modeDir           = 0o040000 // sparse index contains directory entries
+	modeGitlink       = 0o160000 // submodules
+	indexSHAOffset    = 40
+	indexSHASize      = 20
+)
+
+// readGitIndex reads the git index of the repository located in repoRoot
+func readGitIndex(repoRoot string) (*gitIndex, error) {
+	gitDir, err := findGitDir(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(gitDir, "index"))
+	if err != nil {
+		return nil, fmt.Errorf("can't read git index: %w", err)
+	}
+	return parseGitIndex(data)
+}
+
+// findGitDir returns the git directory. '.git' might be a file pointing to the actual git dir (worktrees, submodules)
+func findGitDir(repoRoot string) (string, error) {
+	dotGit := filepath.Join(repoRoot, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return "", fmt.Errorf("can't find git repository in '%s': %w", repoRoot, err)
+	}
+	if info.IsDir() {
+		return dotGit, nil
+	}
+	content, err := os.ReadFile(dotGit)
+	if err != nil {
+		return "", err
+	}
+	gitDir, found := strings.CutPrefix(strings.TrimSpace(string(content)), "gitdir:")
+	if !found {
+		return "", fmt.Errorf("unexpected content of '%s'", dotGit)
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(repoRoot, gitDir)
+	}
+	return gitDir, nil
+}
+
+// parseGitIndex parses the git index file, versions 2, 3 and 4 are supported.
+// https://git-scm.com/docs/index-format
+func parseGitIndex(data []byte) (*gitIndex, error) {
+	if len(data) < indexHeaderSize || !bytes.Equal(data[:4], []byte("DIRC")) {
+		return nil, errors.New("invalid git index: wrong signature")
+	}
+	version := binary.BigEndian.Uint32(data[4:8])
+	if version < 2 || version > 4 {
+		return nil, fmt.Errorf("unsupported git index version %d", version)
+	}
+	count := binary.BigEndian.Uint32(data[8:12])
+
+	idx := &gitIndex{
+		files:    make(map[string]bool, count),
+		dirs:     make(map[string]bool),
+		gitlinks: make(map[string]string),
+	}
+	offset := indexHeaderSize
+	prevName := ""
+	for i := uint32(0); i < count; i++ {
+		if offset+indexEntrySize > len(data) {
+			return nil, errors.New("invalid git index: truncated entry")
+		}
+		mode := binary.BigEndian.Uint32(data[offset+24 : offset+28])
+		sha := hex.EncodeToString(data[offset+indexSHAOffset : offset+indexSHAOffset+indexSHASize])
+		flags := binary.BigEndian.Uint16(data[offset+60 : offset+62])
+		nameStart := offset + indexEntrySize
+		if version >= 3 && flags&indexExtendedFlag != 0 {
+			nameStart += 2
+		}
+
+		var name string
+		if version == 4 {
+			// the name is prefix-compressed: N bytes are removed from the previous name and the suffix is appended
+			strip, n := readIndexVarint(data[nameStart:])
+			if n == 0 || strip > uint64(len(prevName)) {
+				return nil, errors.New("invalid git index: malformed entry name")
+			}
+			suffixStart := nameStart + n
+			end := bytes.IndexByte(data[suffixStart:], 0)
+			if end < 0 {
+				return nil, errors.New("invalid git index: malformed entry name")
+			}
+			name = prevName[:len(prevName)-int(strip)] + string(data[suffixStart:suffixStart+end])
+			offset = suffixStart + end + 1
+		} else {
+			end := bytes.IndexByte(data[nameStart:], 0)
+			if end < 0 {
+				return nil, errors.New("invalid git index: malformed entry name")
+			}
+			if nameLen := int(flags & indexNameMask); nameLen < indexNameMask && nameLen != end {
+				return nil, errors.New("invalid git index: entry name length mismatch")
+			}
+			name = string(data[nameStart : nameStart+end])
+			// entries are padded with 1-8 NUL bytes to keep the size a multiple of eight
+			offset += (nameStart - offset + end + 8) &^ 7
+		}
+		prevName = name
+
+		if mode&modeTypeMask == modeDir {
+			idx.addDir(strings.TrimSuffix(name, "/"))
+			continue
+		}
+		if mode&modeTypeMask == modeGitlink {
+			idx.gitlinks[name] = sha
+		}
+		idx.files[name] = true
+		idx.addDir(path.Dir(name))
+	}
+	return idx, nil
+}
+
+// readIndexVarint reads the variable-length integer used by index v4. Returns the value and the number of bytes read
+func readIndexVarint(data []byte) (uint64, int) {
+	if len(data) == 0 {
+		return 0, 0
+	}
+	c := data[0]
+	val := uint64(c & 0x7f)
+	i := 1
+	for c&0x80 != 0 {
+		if i >= len(data) {
+			return 0, 0
+		}
+		c = data[i]
+		val = ((val + 1) << 7) | uint64(c&0x7f)
+		i++
+	}
+	return val, i
+}
+
+// addDir marks the directory and all its parents as tracked
+func (idx *gitIndex) addDir(dir string) {
+	for dir != "." && dir != "/" && dir != "" && !idx.dirs[dir] {
+		idx.dirs[dir] = true
+		dir = path.Dir(dir)
+	}
+}
+
+// tracked reports whether the path (relative to the repository root, slash-separated) is tracked by git.
+// Directories are considered tracked if they contain at least one tracked file.
+// Files inside submodules are tracked by the submodule, so they are considered tracked as well.
+func (idx *gitIndex) tracked(rel string) bool {
+	if rel == "." || rel == "" {
+		return true
+	}
+	if idx.files[rel] || idx.dirs[rel] {
+		return true
+	}
+	_, ok := idx.submodule(rel)
+	return ok
+}
+
+// submodule returns the path of the submodule containing the given path
+func (idx *gitIndex) submodule(rel string) (string, bool) {
+	for dir := rel; dir != "." && dir != "/" && dir != ""; dir = path.Dir(dir) {
+		if _, ok := idx.gitlinks[dir]; ok {
+			return dir, true
+		}
+	}
+	return "", false
+}
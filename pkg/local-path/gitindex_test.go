@@ -0,0 +1,171 @@
+package local_path
+
+import (
+	"encoding/binary"
+	"errors"
+	"link-validator/pkg/errs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// buildGitIndex generates the git index file content of the given version with the given paths
+func buildGitIndex(version uint32, paths ...string) []byte {
+	data := []byte("DIRC")
+	data = binary.BigEndian.AppendUint32(data, version)
+	data = binary.BigEndian.AppendUint32(data, uint32(len(paths)))
+	prev := ""
+	for _, p := range paths {
+		entry := make([]byte, indexEntrySize)
+		binary.BigEndian.PutUint32(entry[24:28], 0o100644)
+		binary.BigEndian.PutUint16(entry[60:62], uint16(len(p)))
+		if version == 4 {
+			common := 0
+			for common < len(prev) && common < len(p) && prev[common] == p[common] {
+				common++
+			}
+			// the number of stripped bytes is below 128 in tests, so a single byte varint is enough
+			entry = append(entry, byte(len(prev)-common))
+			entry = append(entry, p[common:]...)
+			entry = append(entry, 0)
+		} else {
+			entry = append(entry, p...)
+			padding := 8 - (len(entry) % 8)
+			entry = append(entry, make([]byte, padding)...)
+		}
+		data = append(data, entry...)
+		prev = p
+	}
+	// the checksum is not validated
+	return append(data, make([]byte, 20)...)
+}
+
+func Test_parseGitIndex(t *testing.T) {
+	paths := []string{"README.md", "docs/api/v1/spec.md", "docs/guide.md", "docs/guide2.md"}
+	tests := []struct {
+		name    string
+		data    []byte
+		tracked []string
+		missing []string
+		wantErr bool
+	}{
+		{
+			name:    "index version 2",
+			data:    buildGitIndex(2, paths...),
+			tracked: []string{"README.md", "docs/guide.md", "docs/guide2.md", "docs/api/v1/spec.md", "docs", "docs/api", "docs/api/v1", "."},
+			missing: []string{"generated.md", "docs/api/v2", "docs/guide", "doc"},
+		},
+		{
+			name:    "index version 4 with prefix compression",
+			data:    buildGitIndex(4, paths...),
+			tracked: []string{"README.md", "docs/guide.md", "docs/guide2.md", "docs/api/v1/spec.md", "docs", "docs/api/v1"},
+			missing: []string{"generated.md", "docs/api/v2", "docs/guide"},
+		},
+		{
+			name:    "wrong signature",
+			data:    []byte("NOPE\x00\x00\x00\x02\x00\x00\x00\x00"),
+			wantErr: true,
+		},
+		{
+			name:    "unsupported version",
+			data:    buildGitIndex(5, paths...),
+			wantErr: true,
+		},
+		{
+			name:    "truncated index",
+			data:    buildGitIndex(2, paths...)[:100],
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx, err := parseGitIndex(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseGitIndex() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			for _, p := range tt.tracked {
+				if !idx.tracked(p) {
+					t.Errorf("expected '%s' to be tracked", p)
+				}
+			}
+			for _, p := range tt.missing {
+				if idx.tracked(p) {
+					t.Errorf("expected '%s' not to be tracked", p)
+				}
+			}
+		})
+	}
+}
+
+func TestLinkProcessor_checkTracked(t *testing.T) {
+	tmp := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmp, ".git"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	index := buildGitIndex(2, "README.md", "docs/guide.md")
+	if err := os.WriteFile(filepath.Join(tmp, ".git", "index"), index, 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	idx, err := readGitIndex(tmp)
+	if err != nil {
+		t.Fatalf("readGitIndex() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		index      *gitIndex
+		targetPath string
+		wantErr    bool
+		wantIs     error
+	}{
+		{
+			name:       "tracked file",
+			index:      idx,
+			targetPath: filepath.Join(tmp, "docs", "guide.md"),
+		},
+		{
+			name:       "directory with tracked files",
+			index:      idx,
+			targetPath: filepath.Join(tmp, "docs"),
+		},
+		{
+			name:       "untracked file",
+			index:      idx,
+			targetPath: filepath.Join(tmp, "docs", "generated.md"),
+			wantErr:    true,
+			wantIs:     errs.ErrNotTracked,
+		},
+		{
+			name:       "directory without tracked files",
+			index:      idx,
+			targetPath: filepath.Join(tmp, "build"),
+			wantErr:    true,
+			wantIs:     errs.ErrNotTracked,
+		},
+		{
+			name:       "file outside of the repository is not checked",
+			index:      idx,
+			targetPath: filepath.Join(filepath.Dir(tmp), "other-repo", "README.md"),
+		},
+		{
+			name:       "tracked-only mode is off",
+			targetPath: filepath.Join(tmp, "docs", "generated.md"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proc := &LinkProcessor{repoRoot: tmp, index: tt.index}
+			err := proc.checkTracked(tt.targetPath)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkTracked() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+				t.Errorf("expected \n errors.Is(err, %v) to be true; \n got err=%v", tt.wantIs, err)
+			}
+		})
+	}
+}
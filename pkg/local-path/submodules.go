@@ -0,0 +1,248 @@
+package local_path
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"link-validator/pkg/config"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	SubmodulesSkip   = "skip"
+	SubmodulesGitHub = "github"
+
+	lfsPointerHeader  = "version https://git-lfs.github.com/spec/v1"
+	lfsPointerMaxSize = 1024 // LFS pointers are tiny text files, see https://github.com/git-lfs/git-lfs/blob/main/docs/spec.md
+)
+
+type submodule struct {
+	path string // relative to the repository root, slash-separated
+	url  string
+}
+
+// readGitModules reads submodules declared in '.gitmodules' in the repository root.
+// Returns no submodules if the file doesn't exist.
+func readGitModules(repoRoot string) ([]submodule, error) {
+	f, err := os.Open(filepath.Join(repoRoot, ".gitmodules"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return parseGitModules(f)
+}
+
+// parseGitModules parses the '.gitmodules' file, which is in git config format:
+//
+//	[submodule "name"]
+//		path = libs/foo
+//		url = https://github.com/org/foo.git
+func parseGitModules(r io.Reader) ([]submodule, error) {
+	var result []submodule
+	var current *submodule
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if current != nil && current.path != "" {
+				result = append(result, *current)
+			}
+			current = nil
+			if strings.HasPrefix(line, "[submodule") {
+				current = &submodule{}
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "path":
+			current.path = path.Clean(filepath.ToSlash(value))
+		case "url":
+			current.url = value
+		}
+	}
+	if current != nil && current.path != "" {
+		result = append(result, *current)
+	}
+	return result, scanner.Err()
+}
+
+// findSubmodule returns the submodule containing the path (relative to the repository root, slash-separated)
+// and the path inside the submodule
+func findSubmodule(submodules []submodule, rel string) (*submodule, string, bool) {
+	for i := range submodules {
+		s := &submodules[i]
+		if rel == s.path {
+			return s, "", true
+		}
+		if inner, found := strings.CutPrefix(rel, s.path+"/"); found {
+			return s, inner, true
+		}
+	}
+	return nil, "", false
+}
+
+// checkedOut reports whether the submodule is checked out. Uninitialised submodules are empty directories
+func checkedOut(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	return err == nil && len(entries) > 0
+}
+
+// parseRemote parses the submodule url. Supports https, ssh and scp-like urls, e.g. 'git@github.com:org/repo.git'
+func parseRemote(remote string) (*url.URL, error) {
+	remote = strings.TrimSuffix(strings.TrimSuffix(remote, "/"), ".git")
+	if strings.HasPrefix(remote, "git@") && !strings.Contains(remote, "://") {
+		// scp-like syntax
+		remote = "ssh://" + strings.Replace(remote, ":", "/", 1)
+	}
+	return url.Parse(remote)
+}
+
+// gitHubRepoURL converts the submodule url to the GitHub repository url
+func gitHubRepoURL(remote string) (string, error) {
+	u, err := parseRemote(remote)
+	if err != nil {
+		return "", err
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("can't derive GitHub repository from the submodule url '%s'", remote)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("can't derive GitHub repository from the submodule url '%s'", remote)
+	}
+	return fmt.Sprintf("https://%s/%s/%s", u.Hostname(), parts[0], parts[1]), nil
+}
+
+// isGitHubHost reports whether the links to the host are validated by the GitHub processor,
+// i.e. the host is github.com or one of the configured GitHub Enterprise instances
+func (proc *LinkProcessor) isGitHubHost(hostname string) bool {
+	hostname = strings.ToLower(hostname)
+	return hostname == "github.com" || hostname == "www.github.com" || proc.enterpriseHosts[hostname]
+}
+
+// enterpriseHostnames returns the hostnames of the configured GitHub Enterprise instances
+func enterpriseHostnames(cfg config.GitHubConfig) map[string]bool {
+	hosts := cfg.Hosts
+	if cfg.CorpGitHubUrl != "" {
+		hosts = append([]config.GitHubHost{{Url: cfg.CorpGitHubUrl}}, hosts...)
+	}
+	hostnames := make(map[string]bool)
+	for _, host := range hosts {
+		if hostname, err := host.Hostname(); err == nil {
+			hostnames[hostname] = true
+		}
+	}
+	return hostnames
+}
+
+// readOriginURL returns the url of the 'origin' remote from the git config of the repository, "" if there is none.
+// Worktrees share the config of the main repository, it is found via 'commondir'.
+func readOriginURL(repoRoot string) (string, error) {
+	gitDir, err := findGitDir(repoRoot)
+	if err != nil {
+		return "", err
+	}
+	if common, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir := strings.TrimSpace(string(common))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+		gitDir = commonDir
+	}
+	f, err := os.Open(filepath.Join(gitDir, "config"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	inOrigin := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inOrigin = line == `[remote "origin"]`
+			continue
+		}
+		if !inOrigin {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if found && strings.ToLower(strings.TrimSpace(key)) == "url" {
+			return strings.Trim(strings.TrimSpace(value), `"`), nil
+		}
+	}
+	return "", scanner.Err()
+}
+
+// isRelativeURL reports whether the submodule url is relative to the superproject remote, e.g. '../other-repo.git'
+func isRelativeURL(remote string) bool {
+	return strings.HasPrefix(remote, "./") || strings.HasPrefix(remote, "../")
+}
+
+// resolveRelativeURL resolves the relative submodule url against the superproject remote url the way git does it:
+// '../other-repo.git' relative to 'https://github.com/org/repo.git' is 'https://github.com/org/other-repo.git'
+func resolveRelativeURL(remote, rel string) (string, error) {
+	base, path := strings.TrimSuffix(remote, "/"), rel
+	for {
+		switch {
+		case strings.HasPrefix(path, "./"):
+			path = path[len("./"):]
+		case strings.HasPrefix(path, "../"):
+			path = path[len("../"):]
+			i := strings.LastIndexAny(base, "/:")
+			if i < 0 || strings.HasSuffix(base, ":") {
+				return "", fmt.Errorf("can't resolve the submodule url '%s' against '%s'", rel, remote)
+			}
+			if base[i] == ':' {
+				// the host of the scp-like url 'git@github.com:org' keeps its separator
+				i++
+			}
+			base = base[:i]
+		default:
+			sep := "/"
+			if strings.HasSuffix(base, ":") {
+				sep = ""
+			}
+			return base + sep + path, nil
+		}
+	}
+}
+
+// isLFSPointer reports whether the file is a Git LFS pointer, i.e. the LFS content isn't present
+func isLFSPointer(targetPath string, size int64) (bool, error) {
+	if size > lfsPointerMaxSize {
+		return false, nil
+	}
+	content, err := os.ReadFile(targetPath)
+	if err != nil {
+		return false, err
+	}
+	return bytes.HasPrefix(content, []byte(lfsPointerHeader)), nil
+}
@@ -0,0 +1,340 @@
+package local_path
+
+import (
+	"context"
+	"errors"
+	"link-validator/pkg/config"
+	"link-validator/pkg/errs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_parseGitModules(t *testing.T) {
+	content := `# comment
+[submodule "foo"]
+	path = libs/foo
+	url = https://github.com/org/foo.git
+[core]
+	path = ignored
+[submodule "bar"]
+	path = "vendor/bar/"
+	url = git@github.com:org/bar.git
+[submodule "broken"]
+	url = https://github.com/org/broken.git
+`
+	got, err := parseGitModules(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("parseGitModules() error = %v", err)
+	}
+	want := []submodule{
+		{path: "libs/foo", url: "https://github.com/org/foo.git"},
+		{path: "vendor/bar", url: "git@github.com:org/bar.git"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseGitModules() got = %#v, want %#v", got, want)
+	}
+}
+
+func Test_findSubmodule(t *testing.T) {
+	submodules := []submodule{{path: "libs/foo"}, {path: "vendor/bar"}}
+	tests := []struct {
+		name      string
+		rel       string
+		wantPath  string
+		wantInner string
+		wantFound bool
+	}{
+		{name: "submodule root", rel: "libs/foo", wantPath: "libs/foo", wantFound: true},
+		{name: "file in submodule", rel: "libs/foo/docs/README.md", wantPath: "libs/foo", wantInner: "docs/README.md", wantFound: true},
+		{name: "sibling with the same prefix", rel: "libs/foobar/README.md"},
+		{name: "regular file", rel: "docs/README.md"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, inner, found := findSubmodule(submodules, tt.rel)
+			if found != tt.wantFound {
+				t.Fatalf("findSubmodule() found = %v, want %v", found, tt.wantFound)
+			}
+			if !found {
+				return
+			}
+			if sub.path != tt.wantPath || inner != tt.wantInner {
+				t.Errorf("findSubmodule() got = (%s, %s), want (%s, %s)", sub.path, inner, tt.wantPath, tt.wantInner)
+			}
+		})
+	}
+}
+
+func Test_gitHubRepoURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		remote  string
+		want    string
+		wantErr bool
+	}{
+		{name: "https", remote: "https://github.com/org/repo.git", want: "https://github.com/org/repo"},
+		{name: "https without suffix", remote: "https://github.com/org/repo/", want: "https://github.com/org/repo"},
+		{name: "ssh", remote: "ssh://git@github.example.com:22/org/repo.git", want: "https://github.example.com/org/repo"},
+		{name: "scp-like", remote: "git@github.com:org/repo.git", want: "https://github.com/org/repo"},
+		{name: "relative url", remote: "../repo.git", wantErr: true},
+		{name: "not a repository", remote: "https://github.com/org", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := gitHubRepoURL(tt.remote)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("gitHubRepoURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("gitHubRepoURL() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_resolveRelativeURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		remote  string
+		rel     string
+		want    string
+		wantErr bool
+	}{
+		{name: "sibling repository", remote: "https://github.com/org/repo.git", rel: "../other-repo.git", want: "https://github.com/org/other-repo.git"},
+		{name: "trailing slash", remote: "https://github.com/org/repo/", rel: "../other-repo", want: "https://github.com/org/other-repo"},
+		{name: "another owner", remote: "https://github.com/org/repo.git", rel: "../../team/lib.git", want: "https://github.com/team/lib.git"},
+		{name: "below the repository", remote: "https://github.com/org/repo", rel: "./lib.git", want: "https://github.com/org/repo/lib.git"},
+		{name: "scp-like", remote: "git@github.com:org/repo.git", rel: "../other-repo.git", want: "git@github.com:org/other-repo.git"},
+		{name: "scp-like another owner", remote: "git@github.com:org/repo.git", rel: "../../team/lib.git", want: "git@github.com:team/lib.git"},
+		{name: "too many levels", remote: "repo", rel: "../other.git", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveRelativeURL(tt.remote, tt.rel)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveRelativeURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveRelativeURL() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_readOriginURL(t *testing.T) {
+	tmp := t.TempDir()
+	config := `[core]
+	bare = false
+[remote "upstream"]
+	url = https://github.com/upstream/repo.git
+[remote "origin"]
+	url = git@github.com:org/repo.git
+	fetch = +refs/heads/*:refs/remotes/origin/*
+`
+	if err := os.MkdirAll(filepath.Join(tmp, ".git"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, ".git", "config"), []byte(config), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	got, err := readOriginURL(tmp)
+	if err != nil {
+		t.Fatalf("readOriginURL() error = %v", err)
+	}
+	if got != "git@github.com:org/repo.git" {
+		t.Errorf("readOriginURL() got = %s, want git@github.com:org/repo.git", got)
+	}
+}
+
+func Test_enterpriseHostnames(t *testing.T) {
+	cfg := config.GitHubConfig{
+		CorpGitHubUrl: "https://GitHub.MyCorp.com",
+		Hosts:         []config.GitHubHost{{Url: "https://ghes.example.com:8443"}},
+	}
+	got := enterpriseHostnames(cfg)
+	want := map[string]bool{"github.mycorp.com": true, "ghes.example.com": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("enterpriseHostnames() got = %v, want %v", got, want)
+	}
+}
+
+func TestLinkProcessor_Process_submodules(t *testing.T) {
+	tmp := t.TempDir()
+	files := map[string]string{
+		"README.md":         "# Test",
+		"checked/README.md": "# Test",
+		"assets/big.psd":    "version https://git-lfs.github.com/spec/v1\noid sha256:4d7a\nsize 12345\n",
+		"assets/small.txt":  "just text",
+	}
+	for f, content := range files {
+		full := filepath.Join(tmp, f)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatalf("write file: %v", err)
+		}
+	}
+	// uninitialised submodules are empty directories
+	if err := os.MkdirAll(filepath.Join(tmp, "libs/foo"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	submodules := []submodule{
+		{path: "libs/foo", url: "git@github.com:org/foo.git"},
+		{path: "checked", url: "https://github.com/org/checked.git"},
+	}
+	pinned := map[string]string{"libs/foo": "0123456789abcdef0123456789abcdef01234567"}
+	testFile := filepath.Join(tmp, "README.md")
+
+	t.Run("skip mode", func(t *testing.T) {
+		proc := &LinkProcessor{repoRoot: tmp, submodules: submodules, submodulesMode: SubmodulesSkip}
+		if err := proc.Process(context.Background(), "libs/foo/docs/guide.md", testFile); err != nil {
+			t.Errorf("Process() error = %v", err)
+		}
+	})
+
+	t.Run("checked out submodules are validated locally", func(t *testing.T) {
+		proc := &LinkProcessor{repoRoot: tmp, submodules: submodules, submodulesMode: SubmodulesSkip}
+		err := proc.Process(context.Background(), "checked/missing.md", testFile)
+		if !errors.Is(err, errs.ErrNotFound) {
+			t.Errorf("expected \n errors.Is(err, %v) to be true; \n got err=%v", errs.ErrNotFound, err)
+		}
+	})
+
+	t.Run("github mode delegates at the pinned commit", func(t *testing.T) {
+		var got []string
+		proc := &LinkProcessor{
+			repoRoot:       tmp,
+			submodules:     submodules,
+			submodulesMode: SubmodulesGitHub,
+			pinned:         pinned,
+			delegate: func(_ context.Context, url string, _ string) error {
+				got = append(got, url)
+				return nil
+			},
+		}
+		for _, link := range []string{"libs/foo/docs/guide.md#usage", "libs/foo"} {
+			if err := proc.Process(context.Background(), link, testFile); err != nil {
+				t.Errorf("Process() error = %v", err)
+			}
+		}
+		want := []string{
+			"https://github.com/org/foo/blob/0123456789abcdef0123456789abcdef01234567/docs/guide.md#usage",
+			"https://github.com/org/foo/tree/0123456789abcdef0123456789abcdef01234567",
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("delegated urls got = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("github mode skips submodules on other hosts", func(t *testing.T) {
+		proc := &LinkProcessor{
+			repoRoot: tmp,
+			submodules: []submodule{
+				{path: "libs/foo", url: "https://gitlab.com/org/foo.git"},
+			},
+			submodulesMode: SubmodulesGitHub,
+			pinned:         pinned,
+			delegate: func(_ context.Context, url string, _ string) error {
+				t.Errorf("unexpected delegation of %s", url)
+				return nil
+			},
+		}
+		if err := proc.Process(context.Background(), "libs/foo/docs/guide.md", testFile); err != nil {
+			t.Errorf("Process() error = %v", err)
+		}
+	})
+
+	t.Run("github mode delegates submodules on GitHub Enterprise hosts", func(t *testing.T) {
+		var got []string
+		proc := &LinkProcessor{
+			repoRoot:        tmp,
+			submodules:      []submodule{{path: "libs/foo", url: "git@github.mycorp.com:org/foo.git"}},
+			submodulesMode:  SubmodulesGitHub,
+			pinned:          pinned,
+			enterpriseHosts: map[string]bool{"github.mycorp.com": true},
+			delegate: func(_ context.Context, url string, _ string) error {
+				got = append(got, url)
+				return nil
+			},
+		}
+		if err := proc.Process(context.Background(), "libs/foo", testFile); err != nil {
+			t.Errorf("Process() error = %v", err)
+		}
+		want := []string{"https://github.mycorp.com/org/foo/tree/0123456789abcdef0123456789abcdef01234567"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("delegated urls got = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("relative submodule urls are resolved against the origin", func(t *testing.T) {
+		if err := os.MkdirAll(filepath.Join(tmp, ".git"), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		config := "[remote \"origin\"]\n\turl = https://github.com/org/repo.git\n"
+		if err := os.WriteFile(filepath.Join(tmp, ".git", "config"), []byte(config), 0o644); err != nil {
+			t.Fatalf("write file: %v", err)
+		}
+		var got []string
+		proc := &LinkProcessor{
+			repoRoot:       tmp,
+			submodules:     []submodule{{path: "libs/foo", url: "../foo.git"}},
+			submodulesMode: SubmodulesGitHub,
+			pinned:         pinned,
+			delegate: func(_ context.Context, url string, _ string) error {
+				got = append(got, url)
+				return nil
+			},
+		}
+		if err := proc.resolveSubmoduleURLs(); err != nil {
+			t.Fatalf("resolveSubmoduleURLs() error = %v", err)
+		}
+		if err := proc.Process(context.Background(), "libs/foo/docs/guide.md", testFile); err != nil {
+			t.Errorf("Process() error = %v", err)
+		}
+		want := []string{"https://github.com/org/foo/blob/0123456789abcdef0123456789abcdef01234567/docs/guide.md"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("delegated urls got = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("relative submodule urls without the origin are skipped", func(t *testing.T) {
+		noRemote := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(noRemote, ".git"), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		proc := &LinkProcessor{
+			repoRoot:       noRemote,
+			submodules:     []submodule{{path: "libs/foo", url: "../foo.git"}},
+			submodulesMode: SubmodulesGitHub,
+			pinned:         pinned,
+			delegate: func(_ context.Context, url string, _ string) error {
+				t.Errorf("unexpected delegation of %s", url)
+				return nil
+			},
+		}
+		if err := proc.resolveSubmoduleURLs(); err != nil {
+			t.Fatalf("resolveSubmoduleURLs() error = %v", err)
+		}
+		if err := os.MkdirAll(filepath.Join(noRemote, "libs/foo"), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := proc.Process(context.Background(), "libs/foo/docs/guide.md", filepath.Join(noRemote, "README.md")); err != nil {
+			t.Errorf("Process() error = %v", err)
+		}
+	})
+
+	t.Run("lfs pointer", func(t *testing.T) {
+		proc := &LinkProcessor{repoRoot: tmp}
+		err := proc.Process(context.Background(), "assets/big.psd", testFile)
+		if !errors.Is(err, errs.ErrLFSPointer) {
+			t.Errorf("expected \n errors.Is(err, %v) to be true; \n got err=%v", errs.ErrLFSPointer, err)
+		}
+		if err := proc.Process(context.Background(), "assets/small.txt", testFile); err != nil {
+			t.Errorf("Process() error = %v", err)
+		}
+	})
+}
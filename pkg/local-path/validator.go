@@ -1,6 +1,12 @@
 // Package local-path implements local links validation
 // Local links are the links found in the given repository, which point to files in the same repository.
 // Example: [README](../../README.md)
+// Links starting with '/' are resolved against the repository root, the same way GitHub renders them.
+// Example: [Architecture](/docs/arch.md)
+// Links leaving the repository root are reported as errors unless they are explicitly allowed.
+// Optionally, the targets are checked against the git index, so links to untracked or ignored files are reported.
+// Links into submodules which are not checked out are either skipped or delegated to the GitHub processor
+// at the pinned submodule commit. Links to Git LFS pointers, whose content isn't present, are reported.
 
 package local_path
 
@@ -8,19 +14,144 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"link-validator/pkg/config"
+	"link-validator/pkg/docgraph"
 	"link-validator/pkg/errs"
 	"link-validator/pkg/regex"
 	"log/slog"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
+// Delegate validates the link by another processor, e.g. the GitHub processor validates links into submodules
+type Delegate func(ctx context.Context, url string, testFileName string) error
+
 type LinkProcessor struct {
+	repoRoot       string
+	allowOutside   []string
+	index          *gitIndex
+	graph          *docgraph.Graph
+	submodules     []submodule
+	submodulesMode string
+	pinned         map[string]string
+	// enterpriseHosts are the hostnames of GitHub Enterprise instances, links into their submodules are delegated too
+	enterpriseHosts map[string]bool
+	delegate        Delegate
+}
+
+func New(cfg *config.Config, delegate Delegate) (*LinkProcessor, error) {
+	repoRoot := FindRepoRoot(cfg.LookupPath)
+	slog.Debug("local: using repository root", slog.String("path", repoRoot))
+	proc := &LinkProcessor{
+		repoRoot:        repoRoot,
+		allowOutside:    cfg.Validators.LocalPath.AllowOutside,
+		submodulesMode:  cfg.Validators.LocalPath.Submodules,
+		enterpriseHosts: enterpriseHostnames(cfg.Validators.GitHub),
+		delegate:        delegate,
+	}
+	if cfg.Validators.LocalPath.IsTrackedOnly() {
+		index, err := readGitIndex(repoRoot)
+		if err != nil {
+			return nil, fmt.Errorf("can't instantiate local path validator in tracked-only mode: %w", err)
+		}
+		proc.index = index
+	}
+	if err := proc.initSubmodules(); err != nil {
+		return nil, fmt.Errorf("can't instantiate local path validator: %w", err)
+	}
+	if cfg.Validators.LocalPath.DocsGraph.IsEnabled() {
+		entryPoints := cfg.Validators.LocalPath.DocsGraph.EntryPoints
+		if len(entryPoints) == 0 {
+			entryPoints = config.DefaultEntryPoints
+		}
+		proc.graph = docgraph.New(repoRoot, entryPoints)
+	}
+	return proc, nil
+}
+
+// initSubmodules reads submodules and, if links into them are delegated to GitHub, their pinned commits
+func (proc *LinkProcessor) initSubmodules() error {
+	submodules, err := readGitModules(proc.repoRoot)
+	if err != nil {
+		return err
+	}
+	proc.submodules = submodules
+	if len(submodules) == 0 || proc.submodulesMode != SubmodulesGitHub {
+		return nil
+	}
+	if proc.delegate == nil {
+		slog.Warn("local: links into submodules can't be validated by GitHub processor because it is disabled, skipping them")
+		proc.submodulesMode = SubmodulesSkip
+		return nil
+	}
+	index := proc.index
+	if index == nil {
+		index, err = readGitIndex(proc.repoRoot)
+		if err != nil {
+			return err
+		}
+	}
+	proc.pinned = index.gitlinks
+	return proc.resolveSubmoduleURLs()
+}
+
+// resolveSubmoduleURLs resolves relative submodule urls against the 'origin' remote of the repository.
+// If there is no remote, the links into such submodules are skipped.
+func (proc *LinkProcessor) resolveSubmoduleURLs() error {
+	origin, read := "", false
+	for i := range proc.submodules {
+		s := &proc.submodules[i]
+		if !isRelativeURL(s.url) {
+			continue
+		}
+		if !read {
+			var err error
+			if origin, err = readOriginURL(proc.repoRoot); err != nil {
+				return err
+			}
+			read = true
+		}
+		resolved := ""
+		if origin != "" {
+			resolved, _ = resolveRelativeURL(origin, s.url)
+		}
+		if resolved == "" {
+			slog.Warn("local: can't resolve the relative submodule url without the 'origin' remote, skipping links into it",
+				slog.String("submodule", s.path), slog.String("url", s.url))
+		}
+		s.url = resolved
+	}
+	return nil
 }
 
-func New() *LinkProcessor {
-	return &LinkProcessor{}
+// Graph returns the graph of local links between documents, nil if the docs graph analysis is disabled
+func (proc *LinkProcessor) Graph() *docgraph.Graph {
+	return proc.graph
+}
+
+// FindRepoRoot walks up from the lookup path looking for the git toplevel (a directory containing '.git').
+// The '.git' entry can be either a directory or a file (worktrees, submodules).
+// If there is no git repository around, the lookup path itself is considered to be the repository root.
+func FindRepoRoot(lookupPath string) string {
+	if lookupPath == "" {
+		lookupPath = "."
+	}
+	dir, err := filepath.Abs(lookupPath)
+	if err != nil {
+		return lookupPath
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return lookupPath
+		}
+		dir = parent
+	}
 }
 
 func (proc *LinkProcessor) ExtractLinks(line string) []string {
@@ -30,15 +161,17 @@ func (proc *LinkProcessor) ExtractLinks(line string) []string {
 	}
 	urls := make([]string, 0, len(matches))
 	for _, m := range matches {
-		// m[0] = full token "[txt](target)", m[1] = captured target
-		if len(m) > 1 && m[1] != "" {
-			urls = append(urls, m[1])
+		// m[0] = full token "[txt](target)", m[1] = captured target in angle brackets, m[2] = captured plain target
+		for _, target := range m[1:] {
+			if target != "" {
+				urls = append(urls, target)
+			}
 		}
 	}
 	return urls
 }
 
-func (proc *LinkProcessor) Process(_ context.Context, link string, testFileName string) error {
+func (proc *LinkProcessor) Process(ctx context.Context, link string, testFileName string) error {
 	slog.Debug("local: starting validation", slog.String("filename", link))
 
 	// Parse link into file path and optional header
@@ -50,14 +183,36 @@ func (proc *LinkProcessor) Process(_ context.Context, link string, testFileName
 	// Resolve the target file path relative to the test file
 	targetPath := proc.resolveTargetPath(linkPath, testFileName)
 
+	// Links leaving the repository might work locally, but never on GitHub
+	if err := proc.checkInsideRepo(targetPath); err != nil {
+		return err
+	}
+
+	// Submodules might be not checked out, but the links into them are still valid upstream
+	if handled, err := proc.handleSubmodule(ctx, targetPath, header, testFileName); handled {
+		return err
+	}
+
 	// Validate the target file exists and handle directory/header logic
-	return proc.validateTarget(targetPath, header)
+	if err := proc.validateTarget(targetPath, header); err != nil {
+		return err
+	}
+	if proc.graph != nil {
+		proc.graph.AddEdge(testFileName, targetPath)
+	}
+
+	// The target exists in the working copy, but it 404s on GitHub if it isn't committed
+	return proc.checkTracked(targetPath)
 }
 
 // parseLink separates the file path from the optional anchor fragment
 func (proc *LinkProcessor) parseLink(link string) (path, anchor string, err error) {
 	parts := strings.SplitN(link, "#", 2)
-	path = parts[0]
+	// paths can be percent-encoded, e.g. './My%20Guide.md'
+	path, err = url.PathUnescape(parts[0])
+	if err != nil {
+		return "", "", fmt.Errorf("invalid percent-encoding in the link '%s': %w", link, err)
+	}
 
 	if len(parts) > 1 {
 		if parts[1] == "" {
@@ -70,9 +225,9 @@ func (proc *LinkProcessor) parseLink(link string) (path, anchor string, err erro
 }
 
 func (proc *LinkProcessor) resolveTargetPath(linkPath, testFileName string) string {
-	// If linkPath is absolute, return as-is
-	if filepath.IsAbs(linkPath) {
-		return linkPath
+	// If linkPath is absolute, then it is relative to the repository root
+	if strings.HasPrefix(linkPath, "/") {
+		return filepath.Join(proc.repoRoot, linkPath)
 	}
 
 	testDir := filepath.Dir(testFileName)
@@ -89,6 +244,126 @@ func (proc *LinkProcessor) resolveTargetPath(linkPath, testFileName string) stri
 	return targetPath
 }
 
+// checkInsideRepo makes sure the target path doesn't leave the repository root,
+// unless it points into one of the allowed paths
+func (proc *LinkProcessor) checkInsideRepo(targetPath string) error {
+	root, err := filepath.Abs(proc.repoRoot)
+	if err != nil {
+		return err
+	}
+	target, err := filepath.Abs(targetPath)
+	if err != nil {
+		return err
+	}
+	if isWithin(root, target) {
+		return nil
+	}
+	for _, allowed := range proc.allowOutside {
+		if isWithin(filepath.Join(root, allowed), target) {
+			return nil
+		}
+	}
+	return errs.NewOutsideRepo(targetPath)
+}
+
+// isWithin reports whether the path is the dir itself or is nested in it
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// handleSubmodule handles links into submodules, which are not checked out.
+// Returns false if the target is not in a submodule or the submodule is checked out, so the link is validated as usual.
+func (proc *LinkProcessor) handleSubmodule(ctx context.Context, targetPath, header, testFileName string) (bool, error) {
+	if len(proc.submodules) == 0 {
+		return false, nil
+	}
+	root, err := filepath.Abs(proc.repoRoot)
+	if err != nil {
+		return false, err
+	}
+	target, err := filepath.Abs(targetPath)
+	if err != nil {
+		return false, err
+	}
+	rel, err := filepath.Rel(root, target)
+	if err != nil {
+		return false, err
+	}
+	sub, inner, found := findSubmodule(proc.submodules, filepath.ToSlash(rel))
+	if !found {
+		return false, nil
+	}
+	if checkedOut(filepath.Join(root, filepath.FromSlash(sub.path))) {
+		return false, nil
+	}
+
+	if proc.submodulesMode != SubmodulesGitHub {
+		slog.Debug("local: submodule is not checked out, skipping", slog.String("submodule", sub.path), slog.String("path", targetPath))
+		return true, nil
+	}
+
+	if sub.url == "" {
+		slog.Debug("local: submodule url is unknown, skipping", slog.String("submodule", sub.path), slog.String("path", targetPath))
+		return true, nil
+	}
+	remote, err := parseRemote(sub.url)
+	if err != nil {
+		return true, err
+	}
+	if !proc.isGitHubHost(remote.Hostname()) {
+		slog.Debug("local: submodule is not hosted on GitHub, skipping", slog.String("submodule", sub.path), slog.String("url", sub.url))
+		return true, nil
+	}
+	sha, ok := proc.pinned[sub.path]
+	if !ok {
+		return true, fmt.Errorf("can't find the pinned commit of the submodule '%s'", sub.path)
+	}
+	repoURL, err := gitHubRepoURL(sub.url)
+	if err != nil {
+		return true, err
+	}
+	link := fmt.Sprintf("%s/tree/%s", repoURL, sha)
+	if inner != "" {
+		link = fmt.Sprintf("%s/blob/%s/%s", repoURL, sha, inner)
+	}
+	if header != "" {
+		link = fmt.Sprintf("%s#%s", link, header)
+	}
+	slog.Debug("local: submodule is not checked out, delegating to GitHub", slog.String("submodule", sub.path), slog.String("url", link))
+	return true, proc.delegate(ctx, link, testFileName)
+}
+
+// checkTracked checks whether the target is tracked by git. Does nothing if the tracked-only mode is off
+func (proc *LinkProcessor) checkTracked(targetPath string) error {
+	if proc.index == nil {
+		return nil
+	}
+	root, err := filepath.Abs(proc.repoRoot)
+	if err != nil {
+		return err
+	}
+	target, err := filepath.Abs(targetPath)
+	if err != nil {
+		return err
+	}
+	if !isWithin(root, target) {
+		// allowed paths outside the repository can't be checked against the index
+		return nil
+	}
+	rel, err := filepath.Rel(root, target)
+	if err != nil {
+		return err
+	}
+	if !proc.index.tracked(filepath.ToSlash(rel)) {
+		return errs.NewNotTracked(targetPath)
+	}
+	return nil
+}
+
 // validateTarget checks if the target exists and validates directory/header combinations
 func (proc *LinkProcessor) validateTarget(targetPath, header string) error {
 	info, err := os.Stat(targetPath)
@@ -103,6 +378,17 @@ func (proc *LinkProcessor) validateTarget(targetPath, header string) error {
 	if info.IsDir() && header != "" {
 		return errs.NewAnchorLinkToDir(fmt.Sprintf("%s#%s", targetPath, header))
 	}
+	if info.IsDir() {
+		return nil
+	}
 
+	// The file exists, but it might be an LFS pointer without the actual content
+	pointer, err := isLFSPointer(targetPath, info.Size())
+	if err != nil {
+		return err
+	}
+	if pointer {
+		return errs.NewLFSPointer(targetPath)
+	}
 	return nil
 }
@@ -3,17 +3,19 @@ package local_path
 import (
 	"errors"
 	"fmt"
+	"link-validator/pkg/config"
 	"link-validator/pkg/errs"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 )
 
 func TestLinkProcessor_ExtractLinks_LocalOnly(t *testing.T) {
 	t.Parallel()
 
-	proc := New()
+	proc, _ := New(&config.Config{}, nil)
 
 	type tc struct {
 		name string
@@ -55,12 +57,45 @@ func TestLinkProcessor_ExtractLinks_LocalOnly(t *testing.T) {
 			},
 		},
 		{
-			name: "external links are ignored (https, http, mailto, protocol-relative, absolute path)",
-			line: `ext1 [g](https://google.com) ext2 [e](http://example.com) mail [m](mailto:me@ex.com) proto [p](//cdn.example.com/x) abs [r](/root/readme.md) local [l](docs/ok.md)`,
+			name: "external links are ignored (https, http, mailto, protocol-relative)",
+			line: `ext1 [g](https://google.com) ext2 [e](http://example.com) mail [m](mailto:me@ex.com) proto [p](//cdn.example.com/x) local [l](docs/ok.md)`,
 			want: []string{
 				"docs/ok.md",
 			},
 		},
+		{
+			name: "repository-root-relative paths allowed",
+			line: `see [arch](/docs/arch.md) and [setup](/docs/setup.md#install) and [license](/LICENSE)`,
+			want: []string{
+				"/docs/arch.md",
+				"/docs/setup.md#install",
+				"/LICENSE",
+			},
+		},
+		{
+			name: "percent-encoded, unicode and '+' paths allowed",
+			line: `see [guide](./My%20Guide.md) and [c++](docs/c++.md) and [ru](docs/руководство.md#установка) and [jp](./資料/説明.md)`,
+			want: []string{
+				"./My%20Guide.md",
+				"docs/c++.md",
+				"docs/руководство.md#установка",
+				"./資料/説明.md",
+			},
+		},
+		{
+			name: "angle-bracket paths with spaces allowed",
+			line: `see [guide](<./My Guide.md>) and [section](<../docs/Setup Guide.md#first steps>) and [root](</docs/Arch Overview.md>)`,
+			want: []string{
+				"./My Guide.md",
+				"../docs/Setup Guide.md#first steps",
+				"/docs/Arch Overview.md",
+			},
+		},
+		{
+			name: "angle-bracket urls are ignored",
+			line: `ext [g](<https://google.com/a b>) mail [m](<mailto:me@ex.com>) proto [p](<//cdn.example.com/x>)`,
+			want: nil,
+		},
 		{
 			name: "multiple locals mixed with externals",
 			line: `[one](a.md) [two](https://ex.com) [three](b/c.md) [four](mailto:x@y) [five](../d/e.md)`,
@@ -121,10 +156,29 @@ func TestLinkProcessor_parseLink(t *testing.T) {
 			wantHeader: "qqq",
 			wantErr:    false,
 		},
+		{
+			name:       "percent-encoded link is decoded",
+			args:       args{"./My%20Guide%E2%84%A2.md#qqq"},
+			wantPath:   "./My Guide™.md",
+			wantHeader: "qqq",
+			wantErr:    false,
+		},
+		{
+			name:       "plus sign is not decoded",
+			args:       args{"docs/c++.md"},
+			wantPath:   "docs/c++.md",
+			wantHeader: "",
+			wantErr:    false,
+		},
+		{
+			name:    "invalid percent-encoding",
+			args:    args{"./My%2Guide.md"},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			proc := New()
+			proc, _ := New(&config.Config{}, nil)
 			gotPath, gotHeader, err := proc.parseLink(tt.args.link)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("parseLink() error = %v, wantErr %v", err, tt.wantErr)
@@ -238,12 +292,20 @@ func TestLinkProcessor_resolveTargetPath(t *testing.T) {
 			want: "/assets/style.css",
 		},
 		{
-			name: "test file in root, link to nested file",
+			name: "repository-root-relative link is resolved against the repository root",
 			args: args{
 				linkPath:     "/cmd/link-validator/main.go",
 				testFileName: "/docs/README.md",
 			},
-			want: "/cmd/link-validator/main.go",
+			want: "/repo/cmd/link-validator/main.go",
+		},
+		{
+			name: "repository-root-relative link from a nested file",
+			args: args{
+				linkPath:     "/docs/arch.md",
+				testFileName: "docs/api/v1/README.md",
+			},
+			want: "/repo/docs/arch.md",
 		},
 		{
 			name: "don't preserve ./ prefix for relative paths",
@@ -272,7 +334,7 @@ func TestLinkProcessor_resolveTargetPath(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			proc := New()
+			proc := &LinkProcessor{repoRoot: "/repo"}
 			got := proc.resolveTargetPath(tt.args.linkPath, tt.args.testFileName)
 			if got != tt.want {
 				t.Errorf("resolveTargetPath(): %v,\n                                 want: %v", got, tt.want)
@@ -439,7 +501,7 @@ func TestLinkProcessor_validateTarget(t *testing.T) {
 		}
 	}
 
-	proc := New()
+	proc, _ := New(&config.Config{LookupPath: tmp}, nil)
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.fields.fileName != "" {
@@ -473,3 +535,118 @@ func TestLinkProcessor_validateTarget(t *testing.T) {
 		})
 	}
 }
+
+func TestFindRepoRoot(t *testing.T) {
+	tmp := t.TempDir()
+
+	repo := filepath.Join(tmp, "repo")
+	worktree := filepath.Join(tmp, "worktree")
+	noRepo := filepath.Join(tmp, "no-repo")
+	for _, dir := range []string{
+		filepath.Join(repo, ".git"),
+		filepath.Join(repo, "docs", "api"),
+		filepath.Join(worktree, "docs"),
+		filepath.Join(noRepo, "docs"),
+	} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	// worktrees and submodules have '.git' file instead of a directory
+	if err := os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: /somewhere/else"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		lookupPath string
+		want       string
+	}{
+		{
+			name:       "lookup path is the git toplevel",
+			lookupPath: repo,
+			want:       repo,
+		},
+		{
+			name:       "lookup path is nested in the git repository",
+			lookupPath: filepath.Join(repo, "docs", "api"),
+			want:       repo,
+		},
+		{
+			name:       "git toplevel with .git file",
+			lookupPath: filepath.Join(worktree, "docs"),
+			want:       worktree,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindRepoRoot(tt.lookupPath); got != tt.want {
+				t.Errorf("FindRepoRoot() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// tmp dir might be inside a git repository on a developer's machine, so the fallback is checked only when it is not
+	if got := FindRepoRoot(noRepo); got != noRepo && !strings.HasPrefix(noRepo, got) {
+		t.Errorf("FindRepoRoot() = %v, want %v", got, noRepo)
+	}
+}
+
+func TestLinkProcessor_checkInsideRepo(t *testing.T) {
+	tests := []struct {
+		name         string
+		allowOutside []string
+		targetPath   string
+		wantErr      bool
+		wantIs       error
+	}{
+		{
+			name:       "file inside the repository",
+			targetPath: "/work/repo/docs/README.md",
+			wantErr:    false,
+		},
+		{
+			name:       "repository root itself",
+			targetPath: "/work/repo",
+			wantErr:    false,
+		},
+		{
+			name:       "file in the sibling checkout",
+			targetPath: "/work/other-repo/README.md",
+			wantErr:    true,
+			wantIs:     errs.ErrOutsideRepo,
+		},
+		{
+			name:       "directory with the same prefix as the repository",
+			targetPath: "/work/repo-old/README.md",
+			wantErr:    true,
+			wantIs:     errs.ErrOutsideRepo,
+		},
+		{
+			name:         "file in the allowed sibling checkout",
+			allowOutside: []string{"../other-repo"},
+			targetPath:   "/work/other-repo/docs/README.md",
+			wantErr:      false,
+		},
+		{
+			name:         "file in the not allowed sibling checkout",
+			allowOutside: []string{"../other-repo"},
+			targetPath:   "/work/third-repo/README.md",
+			wantErr:      true,
+			wantIs:       errs.ErrOutsideRepo,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proc := &LinkProcessor{repoRoot: "/work/repo", allowOutside: tt.allowOutside}
+			err := proc.checkInsideRepo(tt.targetPath)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkInsideRepo() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+				t.Errorf("expected \n errors.Is(err, %v) to be true; \n got err=%v", tt.wantIs, err)
+			}
+		})
+	}
+}
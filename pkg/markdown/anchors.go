@@ -0,0 +1,115 @@
+// Package markdown implements the parts of GitHub Markdown rendering needed to validate links,
+// e.g. the anchors GitHub generates for headings.
+package markdown
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// UserContentPrefix is added by GitHub to the ids of the rendered headings. Links may contain it.
+const UserContentPrefix = "user-content-"
+
+var (
+	atxHeading    = regexp.MustCompile(`^ {0,3}#{1,6}(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	setextLine    = regexp.MustCompile(`^ {0,3}(?:=+|-+)[ \t]*$`)
+	fence         = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+	inlineLink    = regexp.MustCompile(`!?\[([^\]]*)]\([^)]*\)`)
+	htmlTag       = regexp.MustCompile(`<[^>]+>`)
+	htmlAnchor    = regexp.MustCompile(`<[a-zA-Z][^>]*\s(?:id|name)\s*=\s*["']([^"']+)["']`)
+	markdownFiles = map[string]bool{".md": true, ".markdown": true, ".mdown": true, ".mkdn": true, ".mkd": true}
+)
+
+// IsMarkdown reports whether GitHub renders the file as Markdown
+func IsMarkdown(name string) bool {
+	return markdownFiles[strings.ToLower(path.Ext(name))]
+}
+
+// Anchors returns all anchors of the Markdown document: slugs of headings and ids of HTML elements.
+// Duplicate headings get '-1', '-2', ... suffixes the same way GitHub does it.
+func Anchors(content string) map[string]bool {
+	anchors := make(map[string]bool)
+	counts := make(map[string]int)
+	add := func(heading string) {
+		slug := Slugify(heading)
+		if n, ok := counts[slug]; ok {
+			counts[slug] = n + 1
+			slug = fmt.Sprintf("%s-%d", slug, n+1)
+		} else {
+			counts[slug] = 0
+		}
+		anchors[slug] = true
+	}
+
+	var fenceMarker, prev string
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := fence.FindStringSubmatch(line); m != nil {
+			switch {
+			case fenceMarker == "":
+				fenceMarker = m[1]
+			case strings.HasPrefix(m[1], fenceMarker):
+				fenceMarker = ""
+			}
+			prev = ""
+			continue
+		}
+		if fenceMarker != "" {
+			continue
+		}
+		for _, m := range htmlAnchor.FindAllStringSubmatch(line, -1) {
+			anchors[strings.ToLower(m[1])] = true
+		}
+		if m := atxHeading.FindStringSubmatch(line); m != nil {
+			add(m[1])
+			prev = ""
+			continue
+		}
+		if setextLine.MatchString(line) && strings.TrimSpace(prev) != "" {
+			add(prev)
+			prev = ""
+			continue
+		}
+		prev = line
+	}
+	return anchors
+}
+
+// Slugify converts the heading text into the anchor using the GitHub algorithm: the surrounding whitespace
+// of the heading is trimmed, the text is lower-cased, punctuation is removed and every remaining space
+// is replaced with a dash, e.g. 'Set up  CI' becomes 'set-up--ci'
+func Slugify(heading string) string {
+	// the heading is trimmed before links and tags are removed, so the space after a leading image becomes a dash
+	text := inlineLink.ReplaceAllString(strings.TrimSpace(heading), "$1")
+	text = strings.ToLower(htmlTag.ReplaceAllString(text, ""))
+
+	var sb strings.Builder
+	for _, r := range text {
+		switch {
+		case r == ' ':
+			sb.WriteRune('-')
+		case r == '-' || r == '_':
+			sb.WriteRune(r)
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r):
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// HasAnchor reports whether the anchor (the link fragment) exists in the anchors of the document.
+// The fragment might be percent-encoded and contain the GitHub 'user-content-' prefix, GitHub matches it case-insensitively.
+func HasAnchor(anchors map[string]bool, fragment string) bool {
+	if decoded, err := url.PathUnescape(fragment); err == nil {
+		fragment = decoded
+	}
+	fragment = strings.ToLower(strings.TrimPrefix(fragment, UserContentPrefix))
+	return anchors[fragment]
+}
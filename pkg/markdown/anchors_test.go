@@ -0,0 +1,90 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		heading string
+		want    string
+	}{
+		{heading: "Prerequisites", want: "prerequisites"},
+		{heading: "Getting Started", want: "getting-started"},
+		{heading: "What's new in v2.0?", want: "whats-new-in-v20"},
+		{heading: "`config.yaml` options", want: "configyaml-options"},
+		{heading: "See [the docs](https://example.com) first", want: "see-the-docs-first"},
+		{heading: "snake_case and kebab-case", want: "snake_case-and-kebab-case"},
+		{heading: "Déjà vu — Ünïcode", want: "déjà-vu--ünïcode"},
+		{heading: "Emoji 🚀 rocket", want: "emoji--rocket"},
+		{heading: "<img src=\"logo.png\"> Logo", want: "-logo"},
+		{heading: "  Set up  CI  ", want: "set-up--ci"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.heading, func(t *testing.T) {
+			if got := Slugify(tt.heading); got != tt.want {
+				t.Errorf("Slugify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnchors(t *testing.T) {
+	content := "# Title #\n" +
+		"Intro\n" +
+		"## Usage\n" +
+		"```bash\n" +
+		"# not a heading\n" +
+		"```\n" +
+		"## Usage\n" +
+		"Setext heading\n" +
+		"==============\n" +
+		"Another one\n" +
+		"---\n" +
+		"<a name=\"custom-anchor\"></a>\n" +
+		"<h3 id=\"Raw-HTML\">Raw</h3>\n" +
+		"#hashtag is not a heading\n"
+
+	want := map[string]bool{
+		"title":          true,
+		"usage":          true,
+		"usage-1":        true,
+		"setext-heading": true,
+		"another-one":    true,
+		"custom-anchor":  true,
+		"raw-html":       true,
+	}
+	if got := Anchors(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("Anchors() got = %v, want %v", got, want)
+	}
+}
+
+func TestHasAnchor(t *testing.T) {
+	anchors := map[string]bool{"getting-started": true, "déjà-vu": true}
+	tests := []struct {
+		fragment string
+		want     bool
+	}{
+		{fragment: "getting-started", want: true},
+		{fragment: "Getting-Started", want: true},
+		{fragment: "user-content-getting-started", want: true},
+		{fragment: "d%C3%A9j%C3%A0-vu", want: true},
+		{fragment: "installation", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fragment, func(t *testing.T) {
+			if got := HasAnchor(anchors, tt.fragment); got != tt.want {
+				t.Errorf("HasAnchor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsMarkdown(t *testing.T) {
+	for name, want := range map[string]bool{"README.md": true, "docs/guide.MARKDOWN": true, "main.go": false, "Makefile": false} {
+		if got := IsMarkdown(name); got != want {
+			t.Errorf("IsMarkdown(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
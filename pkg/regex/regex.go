@@ -4,8 +4,9 @@ import "regexp"
 
 // this package contains no tests because the regexes are being tested in the corresponding packages in *_ExtractLinks tests
 
-// GitHub captures almost all GitHub urls including gist.github.com
-var GitHub = regexp.MustCompile(`(?i)https://(?:gist\.)?github\.(?:com|[a-z0-9-]+\.[a-z0-9.-]+)(?:/[^\s\x60\]~"\\<>]*[^\s.,:;!?()\[\]{}\x60~"\\<>])?`)
+// GitHub captures almost all GitHub urls including gist.github.com, raw content (raw.githubusercontent.com, raw.github.[domain]),
+// raw gists (gist.githubusercontent.com) and REST API (api.github.com) urls
+var GitHub = regexp.MustCompile(`(?i)https://(?:(?:gist\.|raw\.)?github\.(?:com|[a-z0-9-]+\.[a-z0-9.-]+)|api\.github\.com|(?:raw|gist)\.githubusercontent\.com)(?:/[^\s\x60\]~"\\<>]*[^\s.,:;!?()\[\]{}\x60~"\\<>])?`)
 
 // EnterpriseGitHub captures only enterprise GitHub urls and used to distinguish between public and enterprise.
 var EnterpriseGitHub = regexp.MustCompile(`github\.[a-z0-9-]+\.[a-z0-9.-]+`)
@@ -13,10 +14,34 @@ var EnterpriseGitHub = regexp.MustCompile(`github\.[a-z0-9-]+\.[a-z0-9.-]+`)
 // Url captures all HTTPS URLs that looks valid, including templated URLs for proper filtering.
 var Url = regexp.MustCompile(`https://(?:[a-zA-Z0-9.\[\]{}$%_-]|<[^>]*>)+(?::[0-9]+)?(?:/[^\s>]*[a-zA-Z0-9/#?&=_\[\]{}$%-]|/)?`)
 
-// LocalPath captures local Markdown links [text](path)
-var LocalPath = regexp.MustCompile(`\[[^]]*]\(((?:\.{1,2}/)*[A-Za-z0-9_.-]+(?:/[A-Za-z0-9_.-]+)*(?:#[^)\s]*)?)\)`)
+// LocalPath captures local Markdown links [text](path), including repository-root-relative ones [text](/path).
+// Path segments may contain unicode, '+' and percent-encoded characters, e.g. [text](./My%20Guide.md).
+// Paths with spaces are captured in the angle-bracket form [text](<./My Guide.md>), the first group captures it,
+// the second group captures the plain form.
+var LocalPath = regexp.MustCompile(`\[[^]]*]\((?:<((?:/|(?:\.{1,2}/)*)[^<>:/\n][^<>:\n]*)>|((?:/|(?:\.{1,2}/)*)[\p{L}\p{M}\p{N}_.+%~-]+(?:/[\p{L}\p{M}\p{N}_.+%~-]+)*(?:#[^)\s]*)?))\)`)
+
+// Wiki captures wiki-style links [[Page Name]], [[Page Name#Heading]] and [[Page Name|label]], including embeds ![[image.png]].
+// The first group captures the page with the optional heading.
+var Wiki = regexp.MustCompile(`\[\[([^\[\]|\n]+)(?:\|[^\[\]\n]*)?]]`)
 
 var DotPattern = regexp.MustCompile(`\.{2,}`)
 
 // DataDog captures all app.datadoghq.com URLs including paths, query parameters, and fragments
 var DataDog = regexp.MustCompile(`(?i)https://app\.datadoghq\.com(?:/[^\s\x60\]~"\\]*[^\s.,:;!?()\[\]{}\x60~"\\])?`)
+
+// LineAnchor captures GitHub line anchors #L10, #L10-L25 and #L10C5-L25C3 (without '#').
+// The first group captures the first line, the second group captures the optional last line.
+var LineAnchor = regexp.MustCompile(`^L(\d+)(?:C\d+)?(?:-L(\d+)(?:C\d+)?)?$`)
+
+// ActionUses captures 'uses:' entries of GitHub Actions workflows and action.yml files, e.g. '- uses: actions/checkout@v4'.
+// The first group captures the reference without quotes and the trailing comment.
+var ActionUses = regexp.MustCompile(`^\s*(?:-\s+)?uses:\s*["']?([^\s"'#]+)`)
+
+// DiffAnchor captures the file anchors of PR and commit diffs #diff-<hash>, #diff-<hash>R31 and #diff-<hash>L10-L12 (without '#').
+// The hash is sha256 of the file path, or md5 in older links. The first group captures the hash,
+// the second and the third groups capture the optional first and last lines with the side, e.g. 'R31'.
+var DiffAnchor = regexp.MustCompile(`^diff-([0-9a-f]{64}|[0-9a-f]{32})(?:([LR]\d+)(?:-([LR]\d+))?)?$`)
+
+// DiffHunk captures the line ranges of the hunk header '@@ -10,7 +10,8 @@' of a unified diff.
+// The groups capture the start and the optional length of the left (old) and the right (new) ranges.
+var DiffHunk = regexp.MustCompile(`(?m)^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
@@ -0,0 +1,137 @@
+// Package wiki implements wiki-style links validation.
+// Wiki links are used by GitHub wiki and Obsidian: [[Page Name]] or [[Page Name|label]].
+// They are resolved against the wiki root using GitHub wiki filename rules:
+// spaces are replaced with dashes, and the page name is case-insensitive.
+// Example: [[Getting Started]] -> Getting-Started.md
+
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"link-validator/pkg/config"
+	"link-validator/pkg/errs"
+	"link-validator/pkg/regex"
+	"log/slog"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// markupExtensions are the file extensions GitHub wiki renders as pages
+var markupExtensions = map[string]bool{
+	".md":        true,
+	".markdown":  true,
+	".mediawiki": true,
+	".wiki":      true,
+	".rst":       true,
+	".textile":   true,
+	".asciidoc":  true,
+	".adoc":      true,
+	".org":       true,
+	".creole":    true,
+	".pod":       true,
+	".rdoc":      true,
+}
+
+type LinkProcessor struct {
+	root string
+	// pages maps normalised page names to the files, files maps normalised file names (with extension) to the files
+	pages map[string]string
+	files map[string]string
+}
+
+func New(cfg *config.Config) (*LinkProcessor, error) {
+	root := cfg.Validators.Wiki.Root
+	if root == "" {
+		root = cfg.LookupPath
+	}
+	proc := &LinkProcessor{
+		root:  root,
+		pages: make(map[string]string),
+		files: make(map[string]string),
+	}
+	if err := proc.index(); err != nil {
+		return nil, fmt.Errorf("can't index wiki root '%s': %w", root, err)
+	}
+	slog.Debug("wiki: indexed", slog.String("root", root), slog.Int("pages", len(proc.pages)))
+	return proc, nil
+}
+
+// index walks the wiki root and collects the pages. GitHub wiki resolves pages by their names
+// regardless of the directory they are in, so subdirectories are flattened.
+func (proc *LinkProcessor) index() error {
+	return filepath.WalkDir(proc.root, proc.visit)
+}
+
+// visit adds the walked file to the index. Only the unreadable root fails the indexing,
+// other entries which can't be read are skipped, the links to them are reported as not found.
+func (proc *LinkProcessor) visit(p string, d fs.DirEntry, err error) error {
+	if err != nil {
+		if p == proc.root {
+			return err
+		}
+		slog.Warn("wiki: can't read, skipping", slog.String("path", p), slog.String("error", err.Error()))
+		return nil
+	}
+	if d.IsDir() {
+		if p != proc.root && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		return nil
+	}
+	name := d.Name()
+	proc.files[normalise(name)] = p
+	ext := strings.ToLower(filepath.Ext(name))
+	if markupExtensions[ext] {
+		page := normalise(strings.TrimSuffix(name, filepath.Ext(name)))
+		if _, exists := proc.pages[page]; !exists {
+			proc.pages[page] = p
+		}
+	}
+	return nil
+}
+
+// normalise applies GitHub wiki filename rules: spaces become dashes, case is ignored
+func normalise(name string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), " ", "-"))
+}
+
+func (proc *LinkProcessor) ExtractLinks(line string) []string {
+	matches := regex.Wiki.FindAllStringSubmatch(line, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+	links := make([]string, 0, len(matches))
+	for _, m := range matches {
+		// m[0] = full token "[[Page#anchor|label]]", m[1] = captured page with the optional anchor
+		target := strings.TrimSpace(m[1])
+		if target == "" || strings.Contains(target, "://") {
+			continue // external links in the wiki syntax are handled by other processors
+		}
+		links = append(links, target)
+	}
+	return links
+}
+
+func (proc *LinkProcessor) Process(_ context.Context, link string, _ string) error {
+	slog.Debug("wiki: starting validation", slog.String("page", link))
+
+	page, _, _ := strings.Cut(link, "#")
+	if page == "" {
+		// [[#Heading]] points to the current page
+		return nil
+	}
+	// Obsidian allows paths in links, but pages are resolved by name
+	name := path.Base(page)
+
+	if _, ok := proc.pages[normalise(name)]; ok {
+		return nil
+	}
+	// links to attachments, e.g. [[diagram.png]], or to pages with the extension
+	if _, ok := proc.files[normalise(name)]; ok {
+		return nil
+	}
+	return errs.NewNotFoundMessage(fmt.Sprintf("wiki page '%s' not found in '%s'", page, proc.root))
+}
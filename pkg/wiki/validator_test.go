@@ -0,0 +1,141 @@
+package wiki
+
+import (
+	"context"
+	"errors"
+	"link-validator/pkg/config"
+	"link-validator/pkg/errs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLinkProcessor_ExtractLinks(t *testing.T) {
+	t.Parallel()
+
+	proc := &LinkProcessor{}
+
+	tests := []struct {
+		name string
+		line string
+		want []string
+	}{
+		{
+			name: "simple wiki links",
+			line: `see [[Home]] and [[Getting Started]]`,
+			want: []string{"Home", "Getting Started"},
+		},
+		{
+			name: "wiki links with labels and headings",
+			line: `see [[Getting Started|the guide]] and [[Design Notes#Storage layer|storage]] and [[#Local heading]]`,
+			want: []string{"Getting Started", "Design Notes#Storage layer", "#Local heading"},
+		},
+		{
+			name: "embeds and paths",
+			line: `![[diagram.png]] and [[notes/Design Notes]]`,
+			want: []string{"diagram.png", "notes/Design Notes"},
+		},
+		{
+			name: "external links in the wiki syntax are ignored",
+			line: `see [[https://github.com/your-ko/link-validator|the repo]] and [[Home]]`,
+			want: []string{"Home"},
+		},
+		{
+			name: "markdown links are ignored",
+			line: `see [Home](Home.md) and [x](https://example.com) and [[ ]]`,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := proc.ExtractLinks(tt.line)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ExtractLinks mismatch\nline=%q\ngot = %#v\nwant= %#v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkProcessor_Process(t *testing.T) {
+	tmp := t.TempDir()
+	for _, f := range []string{
+		"Home.md",
+		"Getting-Started.md",
+		"notes/Design Notes.md",
+		"Release-Process.mediawiki",
+		"images/diagram.png",
+		".git/Hidden-Page.md",
+	} {
+		full := filepath.Join(tmp, f)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(full, []byte("# Test Content"), 0o644); err != nil {
+			t.Fatalf("write file: %v", err)
+		}
+	}
+
+	proc, err := New(&config.Config{Validators: config.ValidatorsConfig{Wiki: config.WikiConfig{Root: tmp}}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		link    string
+		wantErr bool
+		wantIs  error
+	}{
+		{name: "page by name", link: "Home"},
+		{name: "spaces are converted to dashes", link: "Getting Started"},
+		{name: "page names are case-insensitive", link: "getting started"},
+		{name: "page with heading", link: "Getting Started#Installation"},
+		{name: "page in subdirectory with spaces in the file name", link: "Design Notes"},
+		{name: "page with path", link: "notes/Design Notes"},
+		{name: "page in non-markdown markup", link: "Release Process"},
+		{name: "heading in the current page", link: "#Local heading"},
+		{name: "attachment", link: "diagram.png"},
+		{name: "page with extension", link: "Home.md"},
+		{name: "missing page", link: "Roadmap", wantErr: true, wantIs: errs.ErrNotFound},
+		{name: "hidden directories are not indexed", link: "Hidden Page", wantErr: true, wantIs: errs.ErrNotFound},
+		{name: "missing attachment", link: "missing.png", wantErr: true, wantIs: errs.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := proc.Process(context.Background(), tt.link, "Home.md")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Process() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+				t.Errorf("expected \n errors.Is(err, %v) to be true; \n got err=%v", tt.wantIs, err)
+			}
+		})
+	}
+}
+
+func TestNew_missingRoot(t *testing.T) {
+	_, err := New(&config.Config{Validators: config.ValidatorsConfig{Wiki: config.WikiConfig{Root: filepath.Join(t.TempDir(), "missing")}}})
+	if err == nil {
+		t.Errorf("New() expected error for the missing wiki root")
+	}
+}
+
+func TestLinkProcessor_visit_skipsUnreadableEntries(t *testing.T) {
+	root := t.TempDir()
+	proc := &LinkProcessor{root: root, pages: make(map[string]string), files: make(map[string]string)}
+	readErr := errors.New("permission denied")
+
+	if err := proc.visit(filepath.Join(root, "private"), nil, readErr); err != nil {
+		t.Errorf("visit() error = %v, unreadable entries should be skipped", err)
+	}
+	if err := proc.visit(root, nil, readErr); !errors.Is(err, readErr) {
+		t.Errorf("visit() error = %v, the unreadable root should fail the indexing", err)
+	}
+}